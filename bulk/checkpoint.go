@@ -0,0 +1,107 @@
+package bulk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// ChunkResult is what a sent chunk returned
+type ChunkResult struct {
+	Index       int                  `json:"index"`
+	RequestCode string               `json:"requestCode"`
+	SmsItems    []models.SmsItemInfo `json:"smsItems,omitempty"`
+}
+
+// Checkpoint records which chunks of a bulk send were sent. It is safe for
+// concurrent use and can be saved as JSON to resume a send after a crash.
+type Checkpoint struct {
+	mu sync.Mutex
+
+	// ChunkSize is the chunk size the send started with; resuming keeps it
+	ChunkSize int `json:"chunkSize"`
+	// Fingerprint identifies the request and recipient list the checkpoint belongs to
+	Fingerprint string `json:"fingerprint"`
+	// Completed holds the result of every sent chunk by chunk index
+	Completed map[int]ChunkResult `json:"completed"`
+}
+
+// newCheckpoint creates an empty checkpoint for the request with the given fingerprint
+func newCheckpoint(fingerprint string, chunkSize int) *Checkpoint {
+	return &Checkpoint{
+		ChunkSize:   chunkSize,
+		Fingerprint: fingerprint,
+		Completed:   make(map[int]ChunkResult),
+	}
+}
+
+// matches checks that the checkpoint was made for the same request
+func (c *Checkpoint) matches(fingerprint string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ChunkSize <= 0 {
+		return fmt.Errorf("checkpoint has invalid chunk size %d", c.ChunkSize)
+	}
+	if c.Fingerprint != fingerprint {
+		return fmt.Errorf("checkpoint belongs to a different request")
+	}
+	if c.Completed == nil {
+		c.Completed = make(map[int]ChunkResult)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the checkpoint under its lock, so it can be saved while chunks are still being sent
+func (c *Checkpoint) MarshalJSON() ([]byte, error) {
+	type checkpoint Checkpoint
+	return json.Marshal((*checkpoint)(c.snapshot()))
+}
+
+// snapshot returns a copy of the checkpoint that later chunks do not change
+func (c *Checkpoint) snapshot() *Checkpoint {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	completed := make(map[int]ChunkResult, len(c.Completed))
+	for index, result := range c.Completed {
+		completed[index] = result
+	}
+	return &Checkpoint{ChunkSize: c.ChunkSize, Fingerprint: c.Fingerprint, Completed: completed}
+}
+
+func (c *Checkpoint) complete(result ChunkResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Completed[result.Index] = result
+}
+
+func (c *Checkpoint) isCompleted(index int) bool {
+	_, ok := c.result(index)
+	return ok
+}
+
+func (c *Checkpoint) result(index int) (ChunkResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result, ok := c.Completed[index]
+	return result, ok
+}
+
+// fingerprint hashes a request with its recipients, so a checkpoint is not
+// resumed against another list or another message
+func fingerprint(req interface{}) (string, error) {
+	// encoding/json sorts map keys, so pattern parameters hash the same in any order
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fingerprint request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
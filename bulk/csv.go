@@ -0,0 +1,256 @@
+package bulk
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// Columns added to the results CSV
+const (
+	RequestCodeColumn = "requestCode"
+	ErrorColumn       = "error"
+)
+
+const defaultRecipientColumn = "recipient"
+
+// CSVMapping maps the columns of a CSV file to pattern sends
+type CSVMapping struct {
+	// RecipientColumn is the header of the phone number column. Defaults to "recipient".
+	RecipientColumn string
+	// Parameters maps pattern field keys to column headers. Fields missing
+	// from it are read from the column named after the field key.
+	Parameters map[string]string
+}
+
+// CSVResult counts the rows of a CSV send
+type CSVResult struct {
+	Rows    int
+	Sent    int
+	Failed  int
+	Skipped int // rows that already had a request code
+}
+
+// patternField is a pattern field resolved to its CSV column
+type patternField struct {
+	key           string
+	column        int
+	maxCharacters int
+}
+
+// SendPatternCSV sends patternCode once per row of in, with the row's columns as
+// the pattern parameters. The header row is checked against the pattern's
+// fields from GetPatternDetail before anything is sent.
+//
+// Every row is copied to out, in input order, with the requestCode of the send
+// or the error that stopped it. Rows that already have a requestCode are copied
+// without being sent, so feeding the results back in retries the failed rows.
+// The error is only set when the send cannot start or in cannot be read.
+//
+// When ctx carries an idempotency key, every row is sent with its own key
+// derived from it and the row's position, so sending the same file again with
+// the same key does not deliver its rows twice.
+func (s *Sender) SendPatternCSV(ctx context.Context, patternCode string, in io.Reader, out io.Writer, mapping CSVMapping) (*CSVResult, error) {
+	reader := csv.NewReader(in)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	recipientColumn := mapping.RecipientColumn
+	if recipientColumn == "" {
+		recipientColumn = defaultRecipientColumn
+	}
+	recipient, ok := columns[recipientColumn]
+	if !ok {
+		return nil, fmt.Errorf("CSV has no %q column", recipientColumn)
+	}
+
+	detail, err := s.client.GetPatternDetail(ctx, patternCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pattern detail: %w", err)
+	}
+	fields, err := resolveFields(detail, columns, mapping.Parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	// Results go next to the input columns, reusing them when the input is an earlier results file
+	outHeader := header
+	requestCode, ok := columns[RequestCodeColumn]
+	if !ok {
+		requestCode = len(outHeader)
+		outHeader = append(outHeader, RequestCodeColumn)
+	}
+	errorColumn, ok := columns[ErrorColumn]
+	if !ok {
+		errorColumn = len(outHeader)
+		outHeader = append(outHeader, ErrorColumn)
+	}
+
+	writer := csv.NewWriter(out)
+	if err := writer.Write(outHeader); err != nil {
+		return nil, fmt.Errorf("failed to write results CSV: %w", err)
+	}
+	writer.Flush()
+
+	type rowResult struct {
+		record []string
+		status string // "sent", "failed" or "skipped"
+	}
+
+	// pending keeps the rows in input order while at most s.concurrency of them are sent
+	pending := make(chan chan rowResult, s.concurrency)
+	slots := make(chan struct{}, s.concurrency)
+	var readErr error
+	go func() {
+		defer close(pending)
+		for index := 0; ; index++ {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				readErr = fmt.Errorf("failed to read CSV row: %w", err)
+				return
+			}
+
+			row := make([]string, len(outHeader))
+			copy(row, record)
+			done := make(chan rowResult, 1)
+			pending <- done
+
+			if strings.TrimSpace(row[requestCode]) != "" {
+				done <- rowResult{record: row, status: "skipped"}
+				continue
+			}
+
+			slots <- struct{}{}
+			go func(ctx context.Context) {
+				defer func() { <-slots }()
+
+				code, err := s.sendPatternRow(ctx, patternCode, row[recipient], row, fields)
+				row[requestCode], row[errorColumn] = code, ""
+				if err != nil {
+					row[errorColumn] = err.Error()
+					done <- rowResult{record: row, status: "failed"}
+					return
+				}
+				done <- rowResult{record: row, status: "sent"}
+			}(keyedContext(ctx, "row", index))
+		}
+	}()
+
+	result := &CSVResult{}
+	var writeErr error
+	for done := range pending {
+		row := <-done
+		result.Rows++
+		switch row.status {
+		case "sent":
+			result.Sent++
+		case "failed":
+			result.Failed++
+		case "skipped":
+			result.Skipped++
+		}
+
+		if writeErr != nil {
+			continue
+		}
+		if err := writer.Write(row.record); err != nil {
+			writeErr = err
+			continue
+		}
+		// Flush every row so the results survive a crash
+		writer.Flush()
+		writeErr = writer.Error()
+	}
+
+	if readErr != nil {
+		return result, readErr
+	}
+	if writeErr != nil {
+		return result, fmt.Errorf("failed to write results CSV: %w", writeErr)
+	}
+	return result, nil
+}
+
+// sendPatternRow sends a single CSV row and returns its request code
+func (s *Sender) sendPatternRow(ctx context.Context, patternCode, recipient string, row []string, fields []patternField) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" {
+		return "", fmt.Errorf("missing recipient")
+	}
+
+	parameters := make(map[string]string, len(fields))
+	for _, field := range fields {
+		value := strings.TrimSpace(row[field.column])
+		if field.maxCharacters > 0 && utf8.RuneCountInString(value) > field.maxCharacters {
+			return "", fmt.Errorf("parameter %q is longer than %d characters", field.key, field.maxCharacters)
+		}
+		parameters[field.key] = value
+	}
+
+	resp, err := s.client.SendPatternSMS(ctx, models.PatternRequest{
+		Recipients:  []string{recipient},
+		PatternCode: patternCode,
+		Parameters:  parameters,
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.RequestCode, nil
+}
+
+// resolveFields finds the column of every pattern field and rejects mappings
+// to fields the pattern does not have
+func resolveFields(detail *models.PatternDetailResponse, columns map[string]int, parameters map[string]string) ([]patternField, error) {
+	known := make(map[string]bool)
+	var fields []patternField
+	var missing []string
+	for _, field := range detail.Data.ThePattern.Fields {
+		known[field.FieldKey] = true
+
+		name, ok := parameters[field.FieldKey]
+		if !ok {
+			name = field.FieldKey
+		}
+		column, ok := columns[name]
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%s (column %q)", field.FieldKey, name))
+			continue
+		}
+		fields = append(fields, patternField{key: field.FieldKey, column: column, maxCharacters: field.MaxCharacters})
+	}
+
+	var unknown []string
+	for key := range parameters {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	switch {
+	case len(missing) > 0:
+		return nil, fmt.Errorf("CSV is missing pattern fields: %s", strings.Join(missing, ", "))
+	case len(unknown) > 0:
+		return nil, fmt.Errorf("pattern has no fields %s", strings.Join(unknown, ", "))
+	}
+	return fields, nil
+}
@@ -0,0 +1,143 @@
+package bulk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/client"
+)
+
+const otpPattern = `{"data":{"ThePattern":{"GetMessagePatternsByIdResponseField":[{"FieldKey":"code","MaxCharacters":6},{"FieldKey":"name"}]}}}`
+
+func TestSendPatternCSVKeepsRowOrder(t *testing.T) {
+	client := &fakeClient{
+		detail:  otpPattern,
+		failing: map[string]bool{"09120000003": true},
+		// Earlier rows take longer, so the sends finish out of order
+		delay: func(recipient string) time.Duration {
+			return time.Duration(10-int(recipient[len(recipient)-1]-'0')) * time.Millisecond
+		},
+	}
+	sender := NewSender(client, WithConcurrency(4))
+	in := strings.NewReader("recipient,code,name\n" +
+		"09120000001,1111,Ali\n" +
+		"09120000002,2222,Sara\n" +
+		"09120000003,3333,Reza\n" +
+		"09120000004,4444,Mina\n")
+	var out bytes.Buffer
+
+	result, err := sender.SendPatternCSV(context.Background(), "p1", in, &out, CSVMapping{})
+	if err != nil {
+		t.Fatalf("SendPatternCSV() error = %v", err)
+	}
+	if result.Rows != 4 || result.Sent != 3 || result.Failed != 1 || result.Skipped != 0 {
+		t.Errorf("result = %+v, want 4 rows, 3 sent, 1 failed", result)
+	}
+
+	want := "recipient,code,name,requestCode,error\n" +
+		"09120000001,1111,Ali,rc-09120000001,\n" +
+		"09120000002,2222,Sara,rc-09120000002,\n" +
+		"09120000003,3333,Reza,,send to 09120000003 failed\n" +
+		"09120000004,4444,Mina,rc-09120000004,\n"
+	if out.String() != want {
+		t.Errorf("results CSV =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	// Feeding the results back in only retries the failed row
+	client.failing = nil
+	client.sent = nil
+	var retried bytes.Buffer
+	result, err = sender.SendPatternCSV(context.Background(), "p1", strings.NewReader(out.String()), &retried, CSVMapping{})
+	if err != nil {
+		t.Fatalf("retrying SendPatternCSV() error = %v", err)
+	}
+	if result.Sent != 1 || result.Skipped != 3 || len(client.sent) != 1 || client.sent[0][0] != "09120000003" {
+		t.Errorf("retry result = %+v, sends = %v, want only row 3 sent", result, client.sent)
+	}
+	if !strings.Contains(retried.String(), "09120000003,3333,Reza,rc-09120000003,\n") {
+		t.Errorf("retried results CSV =\n%s", retried.String())
+	}
+}
+
+func TestSendPatternCSVChecksFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		mapping CSVMapping
+		want    string
+	}{
+		{"missing field", "recipient,code\n09120000001,1111\n", CSVMapping{}, "missing pattern fields: name"},
+		{"unknown field", "recipient,code,name\n09120000001,1111,Ali\n", CSVMapping{Parameters: map[string]string{"city": "name"}}, "no fields city"},
+		{"missing recipient", "phone,code,name\n09120000001,1111,Ali\n", CSVMapping{}, `no "recipient" column`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{detail: otpPattern}
+			_, err := NewSender(client).SendPatternCSV(context.Background(), "p1", strings.NewReader(tt.csv), &bytes.Buffer{}, tt.mapping)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("SendPatternCSV() error = %v, want %q", err, tt.want)
+			}
+			if len(client.sent) != 0 {
+				t.Errorf("sends = %v, want none", client.sent)
+			}
+		})
+	}
+}
+
+func TestSendPatternCSVMapsColumnsAndLimitsLength(t *testing.T) {
+	client := &fakeClient{detail: otpPattern}
+	in := strings.NewReader("mobile,otp,name\n09120000001,1234567,Ali\n09120000002,123456,Sara\n")
+	var out bytes.Buffer
+
+	result, err := NewSender(client).SendPatternCSV(context.Background(), "p1", in, &out, CSVMapping{
+		RecipientColumn: "mobile",
+		Parameters:      map[string]string{"code": "otp"},
+	})
+	if err != nil {
+		t.Fatalf("SendPatternCSV() error = %v", err)
+	}
+	if result.Sent != 1 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 1 sent and 1 failed", result)
+	}
+	if !strings.Contains(out.String(), `parameter ""code"" is longer than 6 characters`) {
+		t.Errorf("results CSV =\n%s\nwant the MaxCharacters error on row 1", out.String())
+	}
+}
+
+func TestSendPatternCSVKeysEveryRow(t *testing.T) {
+	var sends int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, otpPattern)
+			return
+		}
+		n := atomic.AddInt32(&sends, 1)
+		fmt.Fprintf(w, `{"data":{"requestCode":"rc-%d"}}`, n)
+	}))
+	defer srv.Close()
+
+	c := client.New("key", client.WithBaseURL(srv.URL), client.WithIdempotency(nil, 0))
+	sender := NewSender(c)
+	ctx := client.WithIdempotencyKey(context.Background(), "import-1")
+	csv := "recipient,code,name\n09120000001,1111,Ali\n09120000002,2222,Sara\n"
+
+	for i := 0; i < 2; i++ {
+		result, err := sender.SendPatternCSV(ctx, "p1", strings.NewReader(csv), &bytes.Buffer{}, CSVMapping{})
+		if err != nil {
+			t.Fatalf("SendPatternCSV() error = %v", err)
+		}
+		if result.Sent != 2 {
+			t.Fatalf("result = %+v, want 2 sent", result)
+		}
+	}
+	if got := atomic.LoadInt32(&sends); got != 2 {
+		t.Errorf("sends = %d, want 2 (the second run is deduplicated per row)", got)
+	}
+}
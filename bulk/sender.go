@@ -0,0 +1,256 @@
+// Package bulk sends to large recipient lists: Sender splits them into chunks
+// under Mediana's receiver limit and sends them with bounded parallelism.
+package bulk
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/AryanHamedani/mediana-go-sdk/client"
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+const (
+	defaultChunkSize   = 100
+	defaultConcurrency = 4
+)
+
+// Client is the part of *client.Client the bulk senders use
+type Client interface {
+	GetPatternDetail(ctx context.Context, patternCode string) (*models.PatternDetailResponse, error)
+	SendSMS(ctx context.Context, req models.SMSRequest) (*models.SMSResponse, error)
+	SendPatternSMS(ctx context.Context, req models.PatternRequest) (*models.PatternResponse, error)
+}
+
+// Sender splits recipient lists into chunks and sends them concurrently
+type Sender struct {
+	client       Client
+	chunkSize    int
+	concurrency  int
+	onCheckpoint func(*Checkpoint)
+}
+
+// Option configures a Sender
+type Option func(*Sender)
+
+// WithChunkSize sets the maximum number of recipients per request. Defaults to 100.
+func WithChunkSize(size int) Option {
+	return func(s *Sender) {
+		if size > 0 {
+			s.chunkSize = size
+		}
+	}
+}
+
+// WithConcurrency sets how many chunks are sent at the same time. Defaults to 4.
+func WithConcurrency(concurrency int) Option {
+	return func(s *Sender) {
+		if concurrency > 0 {
+			s.concurrency = concurrency
+		}
+	}
+}
+
+// WithCheckpointHandler is called after every chunk that was sent, so the
+// checkpoint can be persisted and a crashed run resumed. It gets a copy of the
+// checkpoint taken after that chunk, and calls are never concurrent.
+func WithCheckpointHandler(handler func(*Checkpoint)) Option {
+	return func(s *Sender) {
+		s.onCheckpoint = handler
+	}
+}
+
+// NewSender creates a bulk sender on top of client
+func NewSender(client Client, options ...Option) *Sender {
+	s := &Sender{
+		client:      client,
+		chunkSize:   defaultChunkSize,
+		concurrency: defaultConcurrency,
+	}
+
+	for _, opt := range options {
+		opt(s)
+	}
+
+	return s
+}
+
+// ChunkError reports a chunk that could not be sent
+type ChunkError struct {
+	Index      int
+	Recipients []string
+	Err        error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("chunk %d (%d recipients): %v", e.Index, len(e.Recipients), e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// APIError returns the API error that failed the chunk, if any
+func (e *ChunkError) APIError() *errors.APIError {
+	var apiErr *errors.APIError
+	if stderrors.As(e.Err, &apiErr) {
+		return apiErr
+	}
+	return nil
+}
+
+// Result aggregates every chunk of a bulk send
+type Result struct {
+	// RequestCodes holds the request code of every sent chunk, in chunk order
+	RequestCodes []string
+	// SmsItems holds the SMS items of every sent chunk, in chunk order
+	SmsItems []models.SmsItemInfo
+	// Sent is the number of recipients in chunks that were sent
+	Sent int
+	// Failed lists the chunks that could not be sent
+	Failed []*ChunkError
+	// Checkpoint records the sent chunks; pass it back to resume the send
+	Checkpoint *Checkpoint
+}
+
+// Err returns the chunk errors joined together, or nil when every chunk was sent
+func (r *Result) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	errs := make([]error, len(r.Failed))
+	for i, failed := range r.Failed {
+		errs[i] = failed
+	}
+	return stderrors.Join(errs...)
+}
+
+// SendSMS sends req to all its recipients in chunks. Pass the checkpoint of
+// an earlier result of the same request to only send the chunks that were not
+// sent yet, or nil to start over. The error is only set when the send cannot
+// start; chunk failures are reported in the result.
+//
+// When ctx carries an idempotency key, every chunk is sent with its own key
+// derived from it, so a resumed or repeated send skips the chunks that went out.
+func (s *Sender) SendSMS(ctx context.Context, req models.SMSRequest, checkpoint *Checkpoint) (*Result, error) {
+	return s.send(ctx, req, req.Recipients, checkpoint, func(ctx context.Context, recipients []string) (ChunkResult, error) {
+		chunk := req
+		chunk.Recipients = recipients
+		resp, err := s.client.SendSMS(ctx, chunk)
+		if err != nil {
+			return ChunkResult{}, err
+		}
+		return ChunkResult{RequestCode: resp.Data.RequestCode, SmsItems: resp.Data.SmsItems}, nil
+	})
+}
+
+// SendPatternSMS sends req to all its recipients in chunks, like SendSMS
+func (s *Sender) SendPatternSMS(ctx context.Context, req models.PatternRequest, checkpoint *Checkpoint) (*Result, error) {
+	return s.send(ctx, req, req.Recipients, checkpoint, func(ctx context.Context, recipients []string) (ChunkResult, error) {
+		chunk := req
+		chunk.Recipients = recipients
+		resp, err := s.client.SendPatternSMS(ctx, chunk)
+		if err != nil {
+			return ChunkResult{}, err
+		}
+		return ChunkResult{RequestCode: resp.Data.RequestCode, SmsItems: resp.Data.SmsItems}, nil
+	})
+}
+
+// send chunks the recipients of req and runs sendChunk for every chunk missing from the checkpoint
+func (s *Sender) send(ctx context.Context, req interface{}, recipients []string, checkpoint *Checkpoint, sendChunk func(context.Context, []string) (ChunkResult, error)) (*Result, error) {
+	digest, err := fingerprint(req)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint == nil {
+		checkpoint = newCheckpoint(digest, s.chunkSize)
+	} else if err := checkpoint.matches(digest); err != nil {
+		return nil, err
+	}
+
+	chunks := split(recipients, checkpoint.ChunkSize)
+	failures := make([]*ChunkError, len(chunks))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var handlerMu sync.Mutex // serializes onCheckpoint calls
+	for i := 0; i < s.concurrency && i < len(chunks); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				if err := ctx.Err(); err != nil {
+					failures[index] = &ChunkError{Index: index, Recipients: chunks[index], Err: err}
+					continue
+				}
+
+				result, err := sendChunk(keyedContext(ctx, "chunk", index), chunks[index])
+				if err != nil {
+					failures[index] = &ChunkError{Index: index, Recipients: chunks[index], Err: err}
+					continue
+				}
+
+				result.Index = index
+				checkpoint.complete(result)
+				if s.onCheckpoint != nil {
+					handlerMu.Lock()
+					s.onCheckpoint(checkpoint.snapshot())
+					handlerMu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for index := range chunks {
+		if !checkpoint.isCompleted(index) {
+			indexes <- index
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	result := &Result{Checkpoint: checkpoint}
+	for index, chunk := range chunks {
+		if failures[index] != nil {
+			result.Failed = append(result.Failed, failures[index])
+			continue
+		}
+		completed, ok := checkpoint.result(index)
+		if !ok {
+			continue
+		}
+		result.RequestCodes = append(result.RequestCodes, completed.RequestCode)
+		result.SmsItems = append(result.SmsItems, completed.SmsItems...)
+		result.Sent += len(chunk)
+	}
+
+	return result, nil
+}
+
+// keyedContext gives a part of a send its own idempotency key derived from
+// the key ctx carries, since a key applies to exactly one send
+func keyedContext(ctx context.Context, part string, index int) context.Context {
+	key, ok := client.IdempotencyKeyFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return client.WithIdempotencyKey(ctx, key+":"+part+":"+strconv.Itoa(index))
+}
+
+// split cuts recipients into consecutive chunks of at most size recipients
+func split(recipients []string, size int) [][]string {
+	var chunks [][]string
+	for start := 0; start < len(recipients); start += size {
+		end := start + size
+		if end > len(recipients) {
+			end = len(recipients)
+		}
+		chunks = append(chunks, recipients[start:end])
+	}
+	return chunks
+}
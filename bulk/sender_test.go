@@ -0,0 +1,239 @@
+package bulk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/client"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// fakeClient records the recipients of every send and fails the chunks starting with a failing recipient
+type fakeClient struct {
+	mu      sync.Mutex
+	sent    [][]string
+	failing map[string]bool
+	// detail is the JSON returned by GetPatternDetail
+	detail string
+	// delay, when set, is how long a send to the first recipient takes
+	delay func(recipient string) time.Duration
+}
+
+func (f *fakeClient) record(recipients []string) (string, error) {
+	if f.delay != nil {
+		time.Sleep(f.delay(recipients[0]))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failing[recipients[0]] {
+		return "", fmt.Errorf("send to %s failed", recipients[0])
+	}
+	f.sent = append(f.sent, recipients)
+	return "rc-" + recipients[0], nil
+}
+
+func (f *fakeClient) GetPatternDetail(ctx context.Context, patternCode string) (*models.PatternDetailResponse, error) {
+	resp := &models.PatternDetailResponse{}
+	if f.detail != "" {
+		if err := json.Unmarshal([]byte(f.detail), resp); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeClient) SendSMS(ctx context.Context, req models.SMSRequest) (*models.SMSResponse, error) {
+	code, err := f.record(req.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	resp := &models.SMSResponse{}
+	resp.Data.RequestCode = code
+	return resp, nil
+}
+
+func (f *fakeClient) SendPatternSMS(ctx context.Context, req models.PatternRequest) (*models.PatternResponse, error) {
+	code, err := f.record(req.Recipients)
+	if err != nil {
+		return nil, err
+	}
+	resp := &models.PatternResponse{}
+	resp.Data.RequestCode = code
+	return resp, nil
+}
+
+func recipients(n int) []string {
+	list := make([]string, n)
+	for i := range list {
+		list[i] = "0912000" + strconv.Itoa(1000+i)
+	}
+	return list
+}
+
+func TestSendSMSChunksAndResumes(t *testing.T) {
+	list := recipients(10)
+	client := &fakeClient{failing: map[string]bool{list[4]: true}}
+	sender := NewSender(client, WithChunkSize(4), WithConcurrency(2))
+	req := models.SMSRequest{MessageText: "hello", Recipients: list}
+
+	result, err := sender.SendSMS(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("SendSMS() error = %v", err)
+	}
+	if result.Sent != 6 || len(result.Failed) != 1 || result.Failed[0].Index != 1 {
+		t.Fatalf("Sent = %d, Failed = %v, want 6 sent and chunk 1 failed", result.Sent, result.Failed)
+	}
+	if want := []string{"rc-" + list[0], "rc-" + list[8]}; fmt.Sprint(result.RequestCodes) != fmt.Sprint(want) {
+		t.Errorf("RequestCodes = %v, want %v", result.RequestCodes, want)
+	}
+	if result.Err() == nil {
+		t.Error("Err() = nil, want the chunk error")
+	}
+
+	// Resume from a checkpoint that went through JSON, as after a crash
+	saved, err := json.Marshal(result.Checkpoint)
+	if err != nil {
+		t.Fatalf("failed to save checkpoint: %v", err)
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(saved, &checkpoint); err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+
+	client.failing = nil
+	client.sent = nil
+	result, err = sender.SendSMS(context.Background(), req, &checkpoint)
+	if err != nil {
+		t.Fatalf("resumed SendSMS() error = %v", err)
+	}
+	if len(client.sent) != 1 || client.sent[0][0] != list[4] {
+		t.Errorf("resumed sends = %v, want only chunk 1", client.sent)
+	}
+	if result.Sent != 10 || len(result.Failed) != 0 || len(result.RequestCodes) != 3 {
+		t.Errorf("resumed result: Sent = %d, Failed = %v, RequestCodes = %v", result.Sent, result.Failed, result.RequestCodes)
+	}
+}
+
+func TestCheckpointHandlerGetsSerializedCopies(t *testing.T) {
+	var mu sync.Mutex
+	running, calls := false, 0
+	var saved []byte
+	handler := func(checkpoint *Checkpoint) {
+		mu.Lock()
+		if running {
+			t.Error("checkpoint handler called concurrently")
+		}
+		running = true
+		mu.Unlock()
+
+		data, err := json.Marshal(checkpoint)
+		if err != nil {
+			t.Errorf("failed to save checkpoint: %v", err)
+		}
+
+		mu.Lock()
+		running = false
+		calls++
+		saved = data
+		mu.Unlock()
+	}
+
+	sender := NewSender(&fakeClient{}, WithChunkSize(1), WithConcurrency(8), WithCheckpointHandler(handler))
+	result, err := sender.SendSMS(context.Background(), models.SMSRequest{MessageText: "hello", Recipients: recipients(50)}, nil)
+	if err != nil {
+		t.Fatalf("SendSMS() error = %v", err)
+	}
+	if calls != 50 {
+		t.Errorf("handler calls = %d, want 50", calls)
+	}
+
+	var last Checkpoint
+	if err := json.Unmarshal(saved, &last); err != nil {
+		t.Fatalf("failed to load checkpoint: %v", err)
+	}
+	if len(last.Completed) != 50 || len(result.Checkpoint.Completed) != 50 {
+		t.Errorf("last saved checkpoint has %d chunks, want 50", len(last.Completed))
+	}
+}
+
+func TestCheckpointRejectsAnotherRequest(t *testing.T) {
+	sender := NewSender(&fakeClient{}, WithChunkSize(2))
+	req := models.PatternRequest{PatternCode: "p1", Recipients: recipients(4), Parameters: map[string]string{"code": "1234", "name": "Ali"}}
+	result, err := sender.SendPatternSMS(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("SendPatternSMS() error = %v", err)
+	}
+
+	same := req
+	same.Parameters = map[string]string{"name": "Ali", "code": "1234"}
+	if _, err := sender.SendPatternSMS(context.Background(), same, result.Checkpoint); err != nil {
+		t.Errorf("resuming the same request error = %v", err)
+	}
+
+	changes := map[string]func(*models.PatternRequest){
+		"pattern":    func(r *models.PatternRequest) { r.PatternCode = "p2" },
+		"parameters": func(r *models.PatternRequest) { r.Parameters = map[string]string{"code": "9999", "name": "Ali"} },
+		"recipients": func(r *models.PatternRequest) { r.Recipients = recipients(5) },
+	}
+	for name, change := range changes {
+		other := req
+		change(&other)
+		if _, err := sender.SendPatternSMS(context.Background(), other, result.Checkpoint); err == nil {
+			t.Errorf("resuming with other %s error = nil, want a mismatch", name)
+		}
+	}
+
+	text := models.SMSRequest{MessageText: "hello", Recipients: recipients(4)}
+	result, err = sender.SendSMS(context.Background(), text, nil)
+	if err != nil {
+		t.Fatalf("SendSMS() error = %v", err)
+	}
+	text.MessageText = "goodbye"
+	if _, err := sender.SendSMS(context.Background(), text, result.Checkpoint); err == nil {
+		t.Error("resuming with another message text error = nil, want a mismatch")
+	}
+}
+
+func TestSendSMSKeysEveryChunk(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		fmt.Fprintf(w, `{"data":{"requestCode":"rc-%d"}}`, n)
+	}))
+	defer srv.Close()
+
+	c := client.New("key", client.WithBaseURL(srv.URL), client.WithIdempotency(nil, 0))
+	sender := NewSender(c, WithChunkSize(2))
+	ctx := client.WithIdempotencyKey(context.Background(), "campaign-1")
+	req := models.SMSRequest{MessageText: "hello", Recipients: recipients(6)}
+
+	result, err := sender.SendSMS(ctx, req, nil)
+	if err != nil || result.Err() != nil {
+		t.Fatalf("SendSMS() error = %v, %v", err, result.Err())
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Fatalf("API calls = %d, want 3", got)
+	}
+
+	// Starting over with the same key does not send the chunks again
+	again, err := sender.SendSMS(ctx, req, nil)
+	if err != nil || again.Err() != nil {
+		t.Fatalf("repeated SendSMS() error = %v, %v", err, again.Err())
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("API calls after repeating = %d, want 3", got)
+	}
+	if fmt.Sprint(again.RequestCodes) != fmt.Sprint(result.RequestCodes) {
+		t.Errorf("repeated RequestCodes = %v, want %v", again.RequestCodes, result.RequestCodes)
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"sync"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+)
+
+// BreakerState is the state of the client's circuit breaker
+type BreakerState int
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = iota
+	// BreakerOpen rejects every request with errors.ErrCircuitOpen
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of trial requests through
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerSettings configures the circuit breaker
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive 5xx or network failures
+	// that trips the breaker. Defaults to 5.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before allowing trial
+	// requests. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+	// HalfOpenMaxRequests is the number of concurrent trial requests allowed
+	// while half-open. Defaults to 1.
+	HalfOpenMaxRequests int
+	// OnStateChange is called after every state transition
+	OnStateChange func(from, to BreakerState)
+}
+
+// WithCircuitBreaker makes the client fail fast with errors.ErrCircuitOpen
+// after repeated 5xx or network failures, instead of waiting on an API that is down
+func WithCircuitBreaker(settings CircuitBreakerSettings) Option {
+	return func(c *Client) {
+		if settings.FailureThreshold < 1 {
+			settings.FailureThreshold = 5
+		}
+		if settings.OpenTimeout <= 0 {
+			settings.OpenTimeout = 30 * time.Second
+		}
+		if settings.HalfOpenMaxRequests < 1 {
+			settings.HalfOpenMaxRequests = 1
+		}
+		c.breaker = &circuitBreaker{settings: settings}
+	}
+}
+
+// CircuitBreakerState returns the current breaker state; it is always closed
+// when no breaker is configured
+func (c *Client) CircuitBreakerState() BreakerState {
+	if c.breaker == nil {
+		return BreakerClosed
+	}
+
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	return c.breaker.currentState(time.Now())
+}
+
+// attemptOutcome is what a single attempt tells the breaker
+type attemptOutcome int
+
+const (
+	outcomeSuccess attemptOutcome = iota
+	outcomeFailure
+	outcomeIgnored // e.g. cancelled by the caller, says nothing about the API
+)
+
+type circuitBreaker struct {
+	settings CircuitBreakerSettings
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	inFlight int // trial requests in flight while half-open
+	// generation changes with every state transition, so outcomes of requests
+	// allowed in an earlier state are not counted against the current one
+	generation uint64
+}
+
+// currentState moves an expired open breaker to half-open; mu must be held
+func (b *circuitBreaker) currentState(now time.Time) BreakerState {
+	if b.state == BreakerOpen && now.Sub(b.openedAt) >= b.settings.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a request may be sent and returns the generation its
+// outcome must be recorded with
+func (b *circuitBreaker) allow() (uint64, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	from := b.state
+	state := b.currentState(now)
+	if state != from {
+		b.state = state
+		b.inFlight = 0
+		b.generation++
+	}
+	generation := b.generation
+
+	var err error
+	switch state {
+	case BreakerOpen:
+		err = &errors.CircuitOpenError{RetryAt: b.openedAt.Add(b.settings.OpenTimeout)}
+	case BreakerHalfOpen:
+		if b.inFlight >= b.settings.HalfOpenMaxRequests {
+			err = &errors.CircuitOpenError{}
+		} else {
+			b.inFlight++
+		}
+	}
+	b.mu.Unlock()
+
+	b.notify(from, state)
+	return generation, err
+}
+
+// record updates the breaker with the outcome of a request allowed in the
+// given generation; outcomes from an earlier generation are dropped
+func (b *circuitBreaker) record(generation uint64, outcome attemptOutcome) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	from := b.state
+	if from == BreakerHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+
+	switch outcome {
+	case outcomeSuccess:
+		b.failures = 0
+		if from == BreakerHalfOpen {
+			b.state = BreakerClosed
+		}
+	case outcomeFailure:
+		b.failures++
+		if from == BreakerHalfOpen || (from == BreakerClosed && b.failures >= b.settings.FailureThreshold) {
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+		}
+	}
+	to := b.state
+	if to != from {
+		b.generation++
+	}
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+func (b *circuitBreaker) notify(from, to BreakerState) {
+	if from != to && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,209 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+)
+
+func TestCircuitBreakerOpensHalfOpensAndCloses(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	var mu sync.Mutex
+	var transitions []string
+	c := New("key", WithBaseURL(srv.URL), WithCircuitBreaker(CircuitBreakerSettings{
+		FailureThreshold: 2,
+		OpenTimeout:      50 * time.Millisecond,
+		OnStateChange: func(from, to BreakerState) {
+			mu.Lock()
+			defer mu.Unlock()
+			transitions = append(transitions, from.String()+"->"+to.String())
+		},
+	}))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		c.GetAccountBalance(ctx)
+	}
+	if state := c.CircuitBreakerState(); state != BreakerOpen {
+		t.Fatalf("state after 2 failures = %v, want open", state)
+	}
+
+	_, err := c.GetAccountBalance(ctx)
+	var openErr *errors.CircuitOpenError
+	if !errors.Is(err, errors.ErrCircuitOpen) || !errors.As(err, &openErr) || openErr.RetryAt.IsZero() {
+		t.Fatalf("error while open = %v, want a CircuitOpenError with RetryAt", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("calls = %d, want 2 (open breaker must not call the API)", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if state := c.CircuitBreakerState(); state != BreakerHalfOpen {
+		t.Fatalf("state after timeout = %v, want half-open", state)
+	}
+
+	// A failed trial reopens the breaker
+	c.GetAccountBalance(ctx)
+	if state := c.CircuitBreakerState(); state != BreakerOpen {
+		t.Fatalf("state after failed trial = %v, want open", state)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	failing.Store(false)
+	if _, err := c.GetAccountBalance(ctx); err != nil {
+		t.Fatalf("trial request error = %v", err)
+	}
+	if state := c.CircuitBreakerState(); state != BreakerClosed {
+		t.Fatalf("state after successful trial = %v, want closed", state)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"closed->open", "open->half-open", "half-open->open", "open->half-open", "half-open->closed"}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v, want %v", transitions, want)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1}))
+	for i := 0; i < 3; i++ {
+		c.GetAccountBalance(context.Background())
+	}
+	if state := c.CircuitBreakerState(); state != BreakerClosed {
+		t.Errorf("state after 4xx responses = %v, want closed", state)
+	}
+}
+
+func TestCircuitBreakerLimitsHalfOpenTrials(t *testing.T) {
+	release := make(chan struct{})
+	var failing atomic.Bool
+	failing.Store(true)
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		<-release
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithCircuitBreaker(CircuitBreakerSettings{
+		FailureThreshold: 1,
+		OpenTimeout:      10 * time.Millisecond,
+	}))
+	c.GetAccountBalance(context.Background())
+	time.Sleep(20 * time.Millisecond)
+	failing.Store(false)
+
+	done := make(chan error)
+	go func() {
+		_, err := c.GetAccountBalance(context.Background())
+		done <- err
+	}()
+
+	// While the single trial is running, other calls are rejected
+	for atomic.LoadInt32(calls) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	_, err := c.GetAccountBalance(context.Background())
+	close(release)
+	if !errors.Is(err, errors.ErrCircuitOpen) {
+		t.Errorf("call during the half-open trial error = %v, want ErrCircuitOpen", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("trial request error = %v", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresOutcomesFromEarlierStates(t *testing.T) {
+	releaseSlow := make(chan struct{})
+	releaseTrial := make(chan struct{})
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		switch call {
+		case 1: // allowed while closed, finishes once the breaker is half-open
+			<-releaseSlow
+		case 2:
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		case 3: // the half-open trial
+			<-releaseTrial
+		}
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithCircuitBreaker(CircuitBreakerSettings{
+		FailureThreshold: 1,
+		OpenTimeout:      20 * time.Millisecond,
+	}))
+	ctx := context.Background()
+	waitCalls := func(n int32) {
+		for atomic.LoadInt32(calls) < n {
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	slow := make(chan error)
+	go func() {
+		_, err := c.GetAccountBalance(ctx)
+		slow <- err
+	}()
+	waitCalls(1)
+
+	c.GetAccountBalance(ctx)
+	if state := c.CircuitBreakerState(); state != BreakerOpen {
+		t.Fatalf("state after failure = %v, want open", state)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	trial := make(chan error)
+	go func() {
+		_, err := c.GetAccountBalance(ctx)
+		trial <- err
+	}()
+	waitCalls(3)
+
+	// The slow success was allowed while closed: it must not close the breaker
+	// nor free the trial slot
+	close(releaseSlow)
+	if err := <-slow; err != nil {
+		t.Fatalf("slow request error = %v", err)
+	}
+	if state := c.CircuitBreakerState(); state != BreakerHalfOpen {
+		t.Errorf("state after the slow success = %v, want half-open", state)
+	}
+	if _, err := c.GetAccountBalance(ctx); !errors.Is(err, errors.ErrCircuitOpen) {
+		t.Errorf("call during the trial error = %v, want ErrCircuitOpen", err)
+	}
+
+	close(releaseTrial)
+	if err := <-trial; err != nil {
+		t.Fatalf("trial request error = %v", err)
+	}
+	if state := c.CircuitBreakerState(); state != BreakerClosed {
+		t.Errorf("state after the trial = %v, want closed", state)
+	}
+}
@@ -3,8 +3,8 @@ package client
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
@@ -17,9 +17,20 @@ const (
 )
 
 type Client struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	baseURL     string
+	apiKey      string
+	httpClient  *http.Client
+	retryPolicy *RetryPolicy
+	idempotency *idempotencyConfig
+	limiter     *rateLimiter
+	breaker     *circuitBreaker
+	middleware  []Middleware
+	handler     Handler
+	maxSegments int
+
+	normalizeRecipients bool
+
+	logRedaction map[string]Redactor
 }
 
 type Option func(*Client)
@@ -34,6 +45,7 @@ func New(apiKey string, options ...Option) *Client {
 	for _, opt := range options {
 		opt(c)
 	}
+	c.handler = c.buildHandler()
 
 	return c
 }
@@ -50,17 +62,106 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
-func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload interface{}) (*http.Response, error) {
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, body []byte, header http.Header) (*http.Response, error) {
 	url := fmt.Sprintf("%s/sms/%s/%s", c.baseURL, apiVersion, endpoint)
 
-	var body bytes.Buffer
-	if payload != nil {
-		if err := json.NewEncoder(&body).Encode(payload); err != nil {
-			return nil, fmt.Errorf("failed to encode payload: %w", err)
+	maxAttempts := c.retryPolicy.maxAttempts(method)
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.wait(ctx, endpoint); err != nil {
+			return nil, notSent(attempt, fmt.Errorf("rate limit wait failed: %w", err))
+		}
+
+		generation, err := c.breaker.allow()
+		if err != nil {
+			return nil, notSent(attempt, err)
+		}
+
+		resp, err := c.send(ctx, method, url, body, header)
+
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+
+		switch {
+		case err != nil && ctx.Err() != nil:
+			c.breaker.record(generation, outcomeIgnored)
+		case err != nil || statusCode >= 500:
+			c.breaker.record(generation, outcomeFailure)
+		default:
+			c.breaker.record(generation, outcomeSuccess)
+		}
+
+		failed := (err != nil && ctx.Err() == nil) || (err == nil && isRetryableStatus(statusCode))
+		retrying := failed && attempt < maxAttempts
+
+		var delay time.Duration
+		if retrying {
+			delay = c.retryPolicy.backoff(attempt)
+			if resp != nil {
+				if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+					delay = retryAfter
+					// Do not block the caller for as long as the server asks
+					retrying = retryAfter <= c.retryPolicy.retryAfterLimit()
+				}
+			}
+			// Give up early rather than sleep past the caller's deadline
+			retrying = retrying && fitsDeadline(ctx, delay)
+		}
+
+		if c.retryPolicy != nil && c.retryPolicy.OnAttempt != nil {
+			c.retryPolicy.OnAttempt(RetryAttempt{
+				Attempt:    attempt,
+				Method:     method,
+				Endpoint:   endpoint,
+				StatusCode: statusCode,
+				Err:        err,
+				Retrying:   retrying,
+				Delay:      delay,
+			})
+		}
+
+		if !retrying {
+			if err != nil {
+				return nil, err
+			}
+			if resp.StatusCode >= 400 {
+				defer resp.Body.Close()
+				return nil, errors.ParseError(resp)
+			}
+			return resp, nil
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("request failed: %w", err)
 		}
 	}
+}
+
+// notSentError marks an error returned before any attempt reached the network
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string { return e.err.Error() }
+
+func (e *notSentError) Unwrap() error { return e.err }
 
-	req, err := http.NewRequestWithContext(ctx, method, url, &body)
+// notSent wraps err in a notSentError when it ended the first attempt
+func notSent(attempt int, err error) error {
+	if attempt > 1 {
+		return err
+	}
+	return &notSentError{err: err}
+}
+
+// send performs a single HTTP attempt
+func (c *Client) send(ctx context.Context, method, url string, body []byte, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -68,16 +169,14 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, payload
 	req.Header.Set("accept", "*/*")
 	req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
-	if resp.StatusCode >= 400 {
-		defer resp.Body.Close()
-		return nil, errors.ParseError(resp)
-	}
-
 	return resp, nil
 }
@@ -0,0 +1,68 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// newTestServer serves every request with handler and counts the calls
+func newTestServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, call int)) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler(w, r, int(atomic.AddInt32(&calls, 1)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func otpRequest() models.OTPRequest {
+	return models.OTPRequest{PatternCode: "otp", Recipient: "09121234567", OTPCode: "1234"}
+}
+
+func TestTransportErrorsAreClassified(t *testing.T) {
+	slow, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		<-r.Context().Done()
+	})
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelDeadline()
+	canceled, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		ctx     context.Context
+		want    errors.Category
+	}{
+		{"connection refused", closed.URL, context.Background(), errors.CategoryRetryable},
+		{"deadline", slow.URL, deadline, errors.CategoryRetryable},
+		{"canceled", slow.URL, canceled, errors.CategoryUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New("key", WithBaseURL(tt.baseURL)).GetAccountBalance(tt.ctx)
+			var urlErr *url.Error
+			if !errors.As(err, &urlErr) {
+				t.Fatalf("error = %v, want a *url.Error", err)
+			}
+			if got := errors.Classify(err); got != tt.want {
+				t.Errorf("Classify(%v) = %v, want %v", err, got, tt.want)
+			}
+		})
+	}
+}
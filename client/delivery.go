@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// DefaultTerminalStatuses returns the item statuses after which a message no
+// longer changes, keyed in lower case: models.TerminalStates
+func DefaultTerminalStatuses() map[string]bool {
+	statuses := make(map[string]bool)
+	for _, state := range models.TerminalStates() {
+		statuses[strings.ToLower(string(state))] = true
+	}
+	return statuses
+}
+
+// WaitOptions configures WaitForDelivery
+type WaitOptions struct {
+	// Timeout bounds the whole wait on top of the context; zero means no extra bound
+	Timeout time.Duration
+	// InitialInterval is the delay before the second poll. Defaults to 2 seconds.
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between polls. Defaults to 30 seconds.
+	MaxInterval time.Duration
+	// Multiplier grows the delay after every poll. Defaults to 1.5.
+	Multiplier float64
+	// TerminalStatuses holds the item statuses, in lower case, that end the
+	// wait. Defaults to DefaultTerminalStatuses.
+	TerminalStatuses map[string]bool
+	// OnProgress is called with every status snapshot
+	OnProgress func(*models.DeliveryStatusResponse)
+}
+
+// WaitForDelivery polls GetDeliveryStatus with backoff until every SMS item of
+// the request reaches a terminal status, or the request itself does when the
+// response lists no items, and returns that final status. When
+// the wait times out, the last snapshot is returned along with the error.
+// Transient errors are retried on the next poll; other errors end the wait.
+func (c *Client) WaitForDelivery(ctx context.Context, requestCode string, opts WaitOptions) (*models.DeliveryStatusResponse, error) {
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = 2 * time.Second
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = 30 * time.Second
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = 1.5
+	}
+	if opts.TerminalStatuses == nil {
+		opts.TerminalStatuses = DefaultTerminalStatuses()
+	}
+
+	var last *models.DeliveryStatusResponse
+	interval := opts.InitialInterval
+	for {
+		resp, err := c.GetDeliveryStatus(ctx, requestCode)
+		switch {
+		case err == nil:
+			last = resp
+			if opts.OnProgress != nil {
+				opts.OnProgress(resp)
+			}
+			if isTerminal(resp, opts.TerminalStatuses) {
+				return resp, nil
+			}
+		case ctx.Err() != nil:
+			return last, fmt.Errorf("waiting for delivery of %s: %w", requestCode, ctx.Err())
+		case !errors.IsRetryable(err):
+			return last, err
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return last, fmt.Errorf("waiting for delivery of %s: %w", requestCode, err)
+		}
+
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// isTerminal reports whether all items of the response have a terminal status,
+// or the request has one when the response lists no items
+func isTerminal(resp *models.DeliveryStatusResponse, terminal map[string]bool) bool {
+	items := resp.Data.SmsItems
+	if len(items) == 0 {
+		return terminal[strings.ToLower(string(resp.Data.Status))]
+	}
+	for _, item := range items {
+		if !terminal[strings.ToLower(string(item.Status))] {
+			return false
+		}
+	}
+	return true
+}
+
+// StatusBatchOptions configures GetDeliveryStatuses
+type StatusBatchOptions struct {
+	// Workers is the number of concurrent lookups. Defaults to 8.
+	Workers int
+}
+
+// GetDeliveryStatuses looks up many request codes concurrently. Every code ends
+// up in exactly one of the returned maps: its status or its error. A failed
+// lookup does not stop the others. Lookups go through the client's rate limiter
+// when one is configured.
+func (c *Client) GetDeliveryStatuses(ctx context.Context, requestCodes []string, opts StatusBatchOptions) (map[string]*models.DeliveryStatusResponse, map[string]error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 8
+	}
+
+	statuses := make(map[string]*models.DeliveryStatusResponse, len(requestCodes))
+	errs := make(map[string]error)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	codes := make(chan string)
+
+	for i := 0; i < workers && i < len(requestCodes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for code := range codes {
+				resp, err := c.GetDeliveryStatus(ctx, code)
+
+				mu.Lock()
+				if err != nil {
+					errs[code] = err
+				} else {
+					statuses[code] = resp
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool, len(requestCodes))
+	for _, code := range requestCodes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes <- code
+	}
+	close(codes)
+	wg.Wait()
+
+	return statuses, errs
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+func TestWaitForDeliveryPollsUntilTerminal(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		status := "Pending"
+		if call >= 3 {
+			status = "delivered"
+		}
+		w.Write([]byte(`{"data":{"smsItems":[{"smsItemId":"1","status":"` + status + `"}]}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL))
+	polls := 0
+	resp, err := c.WaitForDelivery(context.Background(), "rc1", WaitOptions{
+		InitialInterval: time.Millisecond,
+		OnProgress:      func(*models.DeliveryStatusResponse) { polls++ },
+	})
+	if err != nil {
+		t.Fatalf("WaitForDelivery() error = %v", err)
+	}
+	if polls != 3 || resp.Data.SmsItems[0].Status != "delivered" {
+		t.Errorf("polls = %d, status = %q; want 3, delivered", polls, resp.Data.SmsItems[0].Status)
+	}
+}
+
+func TestWaitForDeliveryCustomTerminalStatuses(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{"smsItems":[{"smsItemId":"1","status":"Sent"}]}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL))
+	_, err := c.WaitForDelivery(context.Background(), "rc1", WaitOptions{
+		InitialInterval:  time.Millisecond,
+		TerminalStatuses: map[string]bool{"sent": true},
+	})
+	if err != nil {
+		t.Fatalf("WaitForDelivery() error = %v", err)
+	}
+
+	_, err = c.WaitForDelivery(context.Background(), "rc1", WaitOptions{
+		Timeout:         30 * time.Millisecond,
+		InitialInterval: time.Millisecond,
+	})
+	if err == nil {
+		t.Fatal("WaitForDelivery() error = nil, want a timeout while items are only sent")
+	}
+}
+
+func TestWaitForDeliveryUsesRequestStatusWithoutItems(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		status := "Queued"
+		if call >= 2 {
+			status = "Delivered"
+		}
+		w.Write([]byte(`{"data":{"status":"` + status + `","smsItems":[]}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := c.WaitForDelivery(ctx, "rc1", WaitOptions{InitialInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("WaitForDelivery() error = %v", err)
+	}
+	if resp.Data.Status != models.StateDelivered {
+		t.Errorf("status = %q, want Delivered", resp.Data.Status)
+	}
+}
+
+func TestDefaultTerminalStatusesMatchModels(t *testing.T) {
+	statuses := DefaultTerminalStatuses()
+	if len(statuses) != len(models.TerminalStates()) {
+		t.Errorf("DefaultTerminalStatuses() = %v, want one entry per models.TerminalStates()", statuses)
+	}
+	for status := range statuses {
+		if !models.DeliveryState(status).IsTerminal() {
+			t.Errorf("%q is terminal here but not in models", status)
+		}
+	}
+}
+
+func TestGetDeliveryStatusesBoundsWorkersAndKeepsGoing(t *testing.T) {
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+	lookups := make(map[string]int)
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		code := path.Base(r.URL.Path)
+		mu.Lock()
+		lookups[code]++
+		mu.Unlock()
+		if code == "rc3" || code == "rc7" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"meta":{"code":"1045","errorMessage":"invalid request code"}}`))
+			return
+		}
+		fmt.Fprintf(w, `{"data":{"status":"Delivered","smsItems":[{"smsItemId":"%s","status":"Delivered"}]}}`, code)
+	})
+
+	var codes []string
+	for i := 1; i <= 12; i++ {
+		codes = append(codes, fmt.Sprintf("rc%d", i))
+	}
+	codes = append(codes, "rc1", "rc3") // duplicates are looked up once
+
+	c := New("key", WithBaseURL(srv.URL))
+	statuses, errs := c.GetDeliveryStatuses(context.Background(), codes, StatusBatchOptions{Workers: 3})
+
+	if len(statuses) != 10 || len(errs) != 2 {
+		t.Fatalf("statuses = %d, errors = %d, want 10 and 2", len(statuses), len(errs))
+	}
+	for _, code := range []string{"rc3", "rc7"} {
+		if !errors.Is(errs[code], errors.ErrInvalidRequestCode) {
+			t.Errorf("error for %s = %v, want ErrInvalidRequestCode", code, errs[code])
+		}
+	}
+	if got := statuses["rc12"].Data.SmsItems[0].SmsItemId; got != "rc12" {
+		t.Errorf("status of rc12 has item %q", got)
+	}
+	for code, n := range lookups {
+		if n != 1 {
+			t.Errorf("%s looked up %d times, want 1", code, n)
+		}
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 3 || max < 2 {
+		t.Errorf("max concurrent lookups = %d, want at most 3 workers in use", max)
+	}
+}
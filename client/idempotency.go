@@ -0,0 +1,186 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+const defaultIdempotencyWindow = 24 * time.Hour
+
+// IdempotencyState is the state of the send recorded under an idempotency key
+type IdempotencyState int
+
+const (
+	// IdempotencyInFlight marks a send that is still running
+	IdempotencyInFlight IdempotencyState = iota
+	// IdempotencyUnknown marks a send that ended without a clear outcome, such
+	// as a timeout, and may have been delivered
+	IdempotencyUnknown
+	// IdempotencyDone marks a send that succeeded
+	IdempotencyDone
+)
+
+// IdempotencyRecord is what a store keeps for an idempotency key
+type IdempotencyRecord struct {
+	State IdempotencyState
+	// PayloadHash identifies the payload the key was used for
+	PayloadHash string
+	// Response is the raw response of a successful send
+	Response []byte
+}
+
+// IdempotencyStore keeps a record of every keyed send
+type IdempotencyStore interface {
+	// Reserve stores record under key unless the key has a record that has not
+	// expired, as a single atomic step. When it has one, Reserve returns that
+	// record and false.
+	Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (IdempotencyRecord, bool, error)
+	// Set replaces the record stored under key for the given ttl
+	Set(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Delete removes the record stored under key
+	Delete(ctx context.Context, key string) error
+}
+
+type idempotencyConfig struct {
+	store   IdempotencyStore
+	window  time.Duration
+	autoKey bool
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotency enables client-side deduplication of SendSMS, SendPatternSMS
+// and SendOTP. A key is reserved before its send reaches the API, and a later
+// send with the same key within window does not call the API again: it gets
+// the cached response when the first send succeeded, and an error when that
+// send is still running or ended without a clear outcome. A nil store uses an
+// in-memory store and a zero window defaults to 24 hours.
+func WithIdempotency(store IdempotencyStore, window time.Duration) Option {
+	return func(c *Client) {
+		if store == nil {
+			store = NewMemoryIdempotencyStore()
+		}
+		if window <= 0 {
+			window = defaultIdempotencyWindow
+		}
+		autoKey := c.idempotency != nil && c.idempotency.autoKey
+		c.idempotency = &idempotencyConfig{store: store, window: window, autoKey: autoKey}
+	}
+}
+
+// WithAutoIdempotencyKeys derives an idempotency key from the endpoint and
+// payload for sends that were not given one explicitly, so identical sends
+// within the window are only delivered once. It implies WithIdempotency with
+// default settings when that option is not used.
+func WithAutoIdempotencyKeys() Option {
+	return func(c *Client) {
+		if c.idempotency == nil {
+			c.idempotency = &idempotencyConfig{store: NewMemoryIdempotencyStore(), window: defaultIdempotencyWindow}
+		}
+		c.idempotency.autoKey = true
+	}
+}
+
+// WithIdempotencyKey returns a context that carries an explicit idempotency key.
+// A key applies to exactly one send: every send made with the context or a
+// child of it uses the same key, so a send with a different payload fails with
+// errors.ErrIdempotencyKeyReused instead of being sent.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key carried by ctx, if any
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// idempotencyKey returns the store key for a send, or "" when deduplication does not apply
+func (c *Client) idempotencyKey(ctx context.Context, endpoint string, payload []byte) string {
+	if c.idempotency == nil {
+		return ""
+	}
+
+	if key, ok := IdempotencyKeyFromContext(ctx); ok {
+		return endpoint + ":" + key
+	}
+
+	if !c.idempotency.autoKey {
+		return ""
+	}
+
+	return endpoint + ":auto:" + payloadHash(endpoint, payload)
+}
+
+// payloadHash identifies a send's payload in its idempotency record
+func payloadHash(endpoint string, payload []byte) string {
+	sum := sha256.Sum256(append([]byte(endpoint+"\n"), payload...))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore safe for concurrent use
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory store
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]memoryIdempotencyEntry)}
+}
+
+// Reserve stores record under key unless the key has a record that has not expired
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) (IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		return entry.record, false, nil
+	}
+
+	s.set(now, key, record, ttl)
+	return record, true, nil
+}
+
+// Set replaces the record stored under key for the given ttl
+func (s *MemoryIdempotencyStore) Set(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.set(time.Now(), key, record, ttl)
+	return nil
+}
+
+// Delete removes the record stored under key
+func (s *MemoryIdempotencyStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// set stores a record; mu must be held
+func (s *MemoryIdempotencyStore) set(now time.Time, key string, record IdempotencyRecord, ttl time.Duration) {
+	// Drop expired entries from time to time so the map does not grow forever
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, entry := range s.entries {
+			if now.After(entry.expiresAt) {
+				delete(s.entries, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	s.entries[key] = memoryIdempotencyEntry{record: record, expiresAt: now.Add(ttl)}
+}
@@ -0,0 +1,141 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+func TestIdempotencyReplaysSuccessfulSend(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{"requestCode":"rc1"}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithIdempotency(nil, 0))
+	ctx := WithIdempotencyKey(context.Background(), "order-1")
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.SendOTP(ctx, otpRequest())
+		if err != nil {
+			t.Fatalf("SendOTP() error = %v", err)
+		}
+		if resp.Data.RequestCode != "rc1" {
+			t.Errorf("RequestCode = %q, want rc1", resp.Data.RequestCode)
+		}
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestIdempotencyRejectsReusedKey(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{"requestCode":"rc1"}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithIdempotency(nil, 0))
+	ctx := WithIdempotencyKey(context.Background(), "campaign")
+
+	if _, err := c.SendSMS(ctx, models.SMSRequest{Recipients: []string{"09121234567"}, MessageText: "hi"}); err != nil {
+		t.Fatalf("first SendSMS() error = %v", err)
+	}
+	_, err := c.SendSMS(ctx, models.SMSRequest{Recipients: []string{"09351234567"}, MessageText: "hi"})
+	if !errors.Is(err, errors.ErrIdempotencyKeyReused) {
+		t.Fatalf("second SendSMS() error = %v, want ErrIdempotencyKeyReused", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestIdempotencyBlocksConcurrentSend(t *testing.T) {
+	release := make(chan struct{})
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		<-release
+		w.Write([]byte(`{"data":{"requestCode":"rc1"}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithIdempotency(nil, 0))
+	ctx := WithIdempotencyKey(context.Background(), "order-1")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if _, err := c.SendOTP(ctx, otpRequest()); err != nil {
+			t.Errorf("first SendOTP() error = %v", err)
+		}
+	}()
+
+	// Wait until the first send reached the server
+	for atomic.LoadInt32(calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	_, err := c.SendOTP(ctx, otpRequest())
+	close(release)
+	wg.Wait()
+
+	if !errors.Is(err, errors.ErrIdempotencyInFlight) {
+		t.Fatalf("second SendOTP() error = %v, want ErrIdempotencyInFlight", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestIdempotencyBlocksRetryAfterTimeout(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.Write([]byte(`{"data":{"requestCode":"rc1"}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithIdempotency(nil, 0))
+	ctx := WithIdempotencyKey(context.Background(), "order-1")
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.SendOTP(timeoutCtx, otpRequest()); err == nil {
+		t.Fatal("first SendOTP() error = nil, want a timeout")
+	}
+
+	_, err := c.SendOTP(ctx, otpRequest())
+	if !errors.Is(err, errors.ErrIdempotencyOutcomeUnknown) {
+		t.Fatalf("second SendOTP() error = %v, want ErrIdempotencyOutcomeUnknown", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestIdempotencyReleasesRejectedSend(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"meta":{"code":"1041"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"requestCode":"rc2"}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithIdempotency(nil, 0))
+	ctx := WithIdempotencyKey(context.Background(), "order-1")
+
+	if _, err := c.SendOTP(ctx, otpRequest()); err == nil {
+		t.Fatal("first SendOTP() error = nil, want a 400")
+	}
+	resp, err := c.SendOTP(ctx, otpRequest())
+	if err != nil {
+		t.Fatalf("second SendOTP() error = %v", err)
+	}
+	if resp.Data.RequestCode != "rc2" || atomic.LoadInt32(calls) != 2 {
+		t.Errorf("RequestCode = %q after %d calls, want rc2 after 2", resp.Data.RequestCode, atomic.LoadInt32(calls))
+	}
+}
@@ -0,0 +1,126 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// LineSelectorOptions configures a LineSelector
+type LineSelectorOptions struct {
+	// CacheTTL is how long the account's lines are cached. Defaults to 10 minutes.
+	CacheTTL time.Duration
+	// MinValidity skips lines that expire sooner than this from now
+	MinValidity time.Duration
+}
+
+// LineSelector picks the sending number for a send from the account's lines,
+// so it does not have to be hardcoded. It is safe for concurrent use.
+type LineSelector struct {
+	client *Client
+	opts   LineSelectorOptions
+
+	mu        sync.Mutex
+	lines     []models.LineInfo
+	fetchedAt time.Time
+}
+
+// NewLineSelector creates a selector over the lines returned by GetSendingLines
+func NewLineSelector(c *Client, opts LineSelectorOptions) *LineSelector {
+	if opts.CacheTTL <= 0 {
+		opts.CacheTTL = 10 * time.Minute
+	}
+	return &LineSelector{client: c, opts: opts}
+}
+
+// SendingNumber returns the number of the best line for messageType
+func (s *LineSelector) SendingNumber(ctx context.Context, messageType models.MessageType) (string, error) {
+	line, err := s.Select(ctx, messageType)
+	if err != nil {
+		return "", err
+	}
+	return line.Number, nil
+}
+
+// Select returns the best line for messageType: among the lines that support
+// it and stay usable for MinValidity, dedicated lines come first, then the
+// line that stays usable the longest. A line whose expiry date could not be
+// read ranks after the other lines of its kind. It returns
+// errors.ErrNoSendingLine when no line fits.
+func (s *LineSelector) Select(ctx context.Context, messageType models.MessageType) (models.LineInfo, error) {
+	lines, err := s.Lines(ctx)
+	if err != nil {
+		return models.LineInfo{}, err
+	}
+
+	line, ok := SelectLine(lines, messageType, time.Now().Add(s.opts.MinValidity))
+	if !ok {
+		return models.LineInfo{}, fmt.Errorf("%w for %s messages", errors.ErrNoSendingLine, messageType)
+	}
+	return line, nil
+}
+
+// Lines returns the account's lines, fetching them when the cache is stale
+func (s *LineSelector) Lines(ctx context.Context) ([]models.LineInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lines != nil && time.Since(s.fetchedAt) < s.opts.CacheTTL {
+		return s.lines, nil
+	}
+
+	resp, err := s.client.GetSendingLines(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sending lines: %w", err)
+	}
+
+	s.lines = resp.Data
+	s.fetchedAt = time.Now()
+	return s.lines, nil
+}
+
+// Refresh drops the cached lines so the next selection fetches them again
+func (s *LineSelector) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lines = nil
+}
+
+// SelectLine picks the best line for messageType among lines still usable at
+// the given time, the same way LineSelector does
+func SelectLine(lines []models.LineInfo, messageType models.MessageType, at time.Time) (models.LineInfo, bool) {
+	var candidates []models.LineInfo
+	for _, line := range lines {
+		if line.Supports(messageType) && line.UsableAt(at) {
+			candidates = append(candidates, line)
+		}
+	}
+	if len(candidates) == 0 {
+		return models.LineInfo{}, false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.IsDedicated != b.IsDedicated {
+			return a.IsDedicated
+		}
+		// Lines whose expiry could not be read may be about to expire
+		if a.ExpiryUnknown() != b.ExpiryUnknown() {
+			return b.ExpiryUnknown()
+		}
+		// Lines that never expire outlast every other line
+		switch {
+		case a.UsableUntil.IsZero() || b.UsableUntil.IsZero():
+			return a.UsableUntil.IsZero() && !b.UsableUntil.IsZero()
+		default:
+			return a.UsableUntil.After(b.UsableUntil)
+		}
+	})
+	return candidates[0], true
+}
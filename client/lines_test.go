@@ -0,0 +1,42 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+func TestSelectLine(t *testing.T) {
+	now := time.Now()
+	lines := []models.LineInfo{
+		{Number: "expired", IsService: true, UsableUntil: now.Add(-time.Hour)},
+		{Number: "unknown", IsService: true, UsableUntilRaw: "1409/01/01"},
+		{Number: "soon", IsService: true, UsableUntil: now.Add(time.Hour)},
+		{Number: "later", IsService: true, UsableUntil: now.Add(24 * time.Hour)},
+		{Number: "ads", IsAdvertisement: true, IsDedicated: true},
+	}
+
+	tests := []struct {
+		name        string
+		lines       []models.LineInfo
+		messageType models.MessageType
+		want        string
+	}{
+		{"longest usable first", lines, models.MessageTypeService, "later"},
+		{"unknown expiry last", lines[:3], models.MessageTypeService, "soon"},
+		{"unknown expiry over none", lines[:2], models.MessageTypeService, "unknown"},
+		{"message type", lines, models.MessageTypeAdvertisement, "ads"},
+		{"dedicated first", append([]models.LineInfo{{Number: "dedicated", IsService: true, IsDedicated: true, UsableUntil: now.Add(time.Minute)}}, lines...), models.MessageTypeService, "dedicated"},
+	}
+	for _, tt := range tests {
+		line, ok := SelectLine(tt.lines, tt.messageType, now)
+		if !ok || line.Number != tt.want {
+			t.Errorf("%s: SelectLine() = %q, %v; want %q", tt.name, line.Number, ok, tt.want)
+		}
+	}
+
+	if _, ok := SelectLine(lines[:1], models.MessageTypeService, now); ok {
+		t.Error("SelectLine() picked an expired line")
+	}
+}
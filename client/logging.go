@@ -0,0 +1,199 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+)
+
+// Redactor rewrites a single value before it is logged
+type Redactor func(value string) string
+
+// MaskPhone keeps the first and last four digits of a phone number, e.g. 0912***6789
+func MaskPhone(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 8 {
+		return "***"
+	}
+	return string(runes[:4]) + "***" + string(runes[len(runes)-4:])
+}
+
+// Redact hides the value entirely
+func Redact(string) string {
+	return "[REDACTED]"
+}
+
+// DefaultRedactionRules returns the rules applied to logged payloads, keyed by
+// JSON field name: recipients are masked, OTP codes and pattern parameter values
+// are redacted
+func DefaultRedactionRules() map[string]Redactor {
+	return map[string]Redactor{
+		"recipients": MaskPhone,
+		"recipient":  MaskPhone,
+		"otpCode":    Redact,
+		"parameters": Redact,
+	}
+}
+
+// WithLogger logs every API call with its operation, endpoint, status code,
+// latency, Mediana meta code and request code. The request payload is logged
+// after redaction, and the API key is never logged. API errors are logged by
+// their codes and field keys only, since their messages may echo the payload,
+// and phone numbers in other errors are masked.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		if c.logRedaction == nil {
+			c.logRedaction = DefaultRedactionRules()
+		}
+		c.middleware = append(c.middleware, c.loggingMiddleware(logger))
+	}
+}
+
+// WithLogRedaction sets the redaction rule for each given JSON field on top of
+// the defaults. A nil Redactor logs that field unchanged.
+func WithLogRedaction(rules map[string]Redactor) Option {
+	return func(c *Client) {
+		if c.logRedaction == nil {
+			c.logRedaction = DefaultRedactionRules()
+		}
+		for field, redactor := range rules {
+			c.logRedaction[field] = redactor
+		}
+	}
+}
+
+func (c *Client) loggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			start := time.Now()
+			err := next(ctx, call)
+
+			attrs := []slog.Attr{
+				slog.String("operation", call.Operation),
+				slog.String("endpoint", call.Endpoint),
+				slog.Int("status", call.StatusCode),
+				slog.Duration("latency", time.Since(start)),
+			}
+			if call.Payload != nil {
+				attrs = append(attrs, slog.Any("request", redactPayload(call.Payload, c.logRedaction)))
+			}
+			if call.Cached {
+				attrs = append(attrs, slog.Bool("cached", true))
+			}
+
+			if err != nil {
+				attrs = append(attrs, errorAttrs(err)...)
+				logger.LogAttrs(ctx, slog.LevelError, "mediana request failed", attrs...)
+				return err
+			}
+
+			metaCode, requestCode := summarizeResponse(call.ResponseBody)
+			if metaCode != "" {
+				attrs = append(attrs, slog.String("meta_code", metaCode))
+			}
+			if requestCode != "" {
+				attrs = append(attrs, slog.String("request_code", requestCode))
+			}
+			logger.LogAttrs(ctx, slog.LevelInfo, "mediana request", attrs...)
+			return nil
+		}
+	}
+}
+
+// phoneLike matches digit runs long enough to be a phone number, in any script
+var phoneLike = regexp.MustCompile(`\+?\p{Nd}{10,}`)
+
+// errorAttrs describes a failed call without the payload values an error
+// message may echo back
+func errorAttrs(err error) []slog.Attr {
+	var apiErr *errors.APIError
+	if !stderrors.As(err, &apiErr) {
+		return []slog.Attr{slog.String("error", phoneLike.ReplaceAllStringFunc(err.Error(), MaskPhone))}
+	}
+
+	attrs := []slog.Attr{slog.String("error", fmt.Sprintf("API error (%d)", apiErr.StatusCode))}
+	if apiErr.Code != "" {
+		attrs = append(attrs, slog.String("meta_code", apiErr.Code))
+	}
+	if len(apiErr.ErrorCodes) > 0 {
+		codes := make([]int, len(apiErr.ErrorCodes))
+		for i, code := range apiErr.ErrorCodes {
+			codes[i] = int(code)
+		}
+		attrs = append(attrs, slog.Any("error_codes", codes))
+	}
+	if len(apiErr.Fields) > 0 {
+		fields := make([]string, len(apiErr.Fields))
+		for i, field := range apiErr.Fields {
+			fields[i] = fmt.Sprintf("%s:%d", field.Key, int(field.Code))
+		}
+		attrs = append(attrs, slog.Any("error_fields", fields))
+	}
+	return attrs
+}
+
+// summarizeResponse extracts meta.code and data.requestCode from a raw response body
+func summarizeResponse(body []byte) (metaCode, requestCode string) {
+	var envelope struct {
+		Meta struct {
+			Code string `json:"code"`
+		} `json:"meta"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return "", ""
+	}
+
+	var data struct {
+		RequestCode string `json:"requestCode"`
+	}
+	// data is not always an object; a failed decode just means there is no request code
+	_ = json.Unmarshal(envelope.Data, &data)
+
+	return envelope.Meta.Code, data.RequestCode
+}
+
+// redactPayload decodes a JSON payload and applies the redaction rules to its top-level fields
+func redactPayload(payload []byte, rules map[string]Redactor) interface{} {
+	var fields map[string]interface{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return "[UNPARSEABLE]"
+	}
+
+	for field, value := range fields {
+		if redactor := rules[field]; redactor != nil {
+			fields[field] = redactValue(value, redactor)
+		}
+	}
+	return fields
+}
+
+// redactValue applies redactor to a string, to every element of a list and to every value of an object
+func redactValue(value interface{}, redactor Redactor) interface{} {
+	switch v := value.(type) {
+	case string:
+		return redactor(v)
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item, redactor)
+		}
+		return redacted
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			redacted[key] = redactValue(item, redactor)
+		}
+		return redacted
+	case nil:
+		return nil
+	default:
+		return redactor(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+const testAPIKey = "secret-api-key"
+
+// logCall makes one SendPatternSMS through a logging client and returns the decoded log record
+func logCall(t *testing.T, status int, body string, options ...Option) (map[string]interface{}, string) {
+	t.Helper()
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	options = append([]Option{WithBaseURL(srv.URL), WithLogger(logger)}, options...)
+	c := New(testAPIKey, options...)
+	c.SendPatternSMS(context.Background(), models.PatternRequest{
+		Recipients:  []string{"09121234567"},
+		PatternCode: "welcome",
+		Parameters:  map[string]string{"name": "Ali"},
+	})
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("failed to decode log %q: %v", buf.String(), err)
+	}
+	if strings.Contains(buf.String(), testAPIKey) {
+		t.Errorf("log contains the API key: %s", buf.String())
+	}
+	return record, buf.String()
+}
+
+func TestLoggerRedactsPayload(t *testing.T) {
+	record, raw := logCall(t, http.StatusOK, `{"meta":{"code":"200"},"data":{"requestCode":"rc-1"}}`)
+
+	if record["msg"] != "mediana request" || record["operation"] != OpSendPattern || record["request_code"] != "rc-1" || record["meta_code"] != "200" {
+		t.Errorf("log record = %s", raw)
+	}
+	request := record["request"].(map[string]interface{})
+	if got := request["recipients"].([]interface{})[0]; got != "0912***4567" {
+		t.Errorf("logged recipient = %v, want 0912***4567", got)
+	}
+	if got := request["parameters"].(map[string]interface{})["name"]; got != "[REDACTED]" {
+		t.Errorf("logged parameter = %v, want [REDACTED]", got)
+	}
+	if request["patternCode"] != "welcome" {
+		t.Errorf("logged patternCode = %v, want it unchanged", request["patternCode"])
+	}
+	if strings.Contains(raw, "09121234567") || strings.Contains(raw, "Ali") {
+		t.Errorf("log contains payload values: %s", raw)
+	}
+}
+
+func TestLogRedactionOverrides(t *testing.T) {
+	record, _ := logCall(t, http.StatusOK, `{"data":{}}`, WithLogRedaction(map[string]Redactor{
+		"patternCode": Redact,
+		"parameters":  nil,
+	}))
+
+	request := record["request"].(map[string]interface{})
+	if request["patternCode"] != "[REDACTED]" {
+		t.Errorf("logged patternCode = %v, want [REDACTED]", request["patternCode"])
+	}
+	if got := request["parameters"].(map[string]interface{})["name"]; got != "Ali" {
+		t.Errorf("logged parameter = %v, want it unchanged with a nil rule", got)
+	}
+	if got := request["recipients"].([]interface{})[0]; got != "0912***4567" {
+		t.Errorf("logged recipient = %v, want the default rule kept", got)
+	}
+}
+
+func TestLoggerDoesNotLogErrorMessages(t *testing.T) {
+	record, raw := logCall(t, http.StatusBadRequest, `{"meta":{"code":"1041","errorMessage":"bad 09121234567",
+		"errors":[{"key":"recipient","errors":["09121234567 invalid"],"errorCode":1041}]}}`)
+
+	if strings.Contains(raw, "09121234567") {
+		t.Errorf("log contains the echoed number: %s", raw)
+	}
+	if record["level"] != "ERROR" || record["error"] != "API error (400)" || record["meta_code"] != "1041" {
+		t.Errorf("log record = %s", raw)
+	}
+	if fields := record["error_fields"].([]interface{}); len(fields) != 1 || fields[0] != "recipient:1041" {
+		t.Errorf("error_fields = %v, want [recipient:1041]", fields)
+	}
+	if codes := record["error_codes"].([]interface{}); len(codes) != 1 || codes[0] != float64(1041) {
+		t.Errorf("error_codes = %v, want [1041]", codes)
+	}
+}
+
+func TestErrorAttrsMasksPhonesInOtherErrors(t *testing.T) {
+	attrs := errorAttrs(stderrors.New("recipient 09121234567 and ۰۹۱۲۱۲۳۴۵۶۷ rejected"))
+	if got := attrs[0].Value.String(); got != "recipient 0912***4567 and ۰۹۱۲***۴۵۶۷ rejected" {
+		t.Errorf("error = %q", got)
+	}
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// Operation names reported to middleware, matching the operationIds in openapi.yaml
+const (
+	OpSendSMS          = "sendSms"
+	OpSendPattern      = "sendPattern"
+	OpSendOTP          = "sendOtp"
+	OpDeliveryStatus   = "deliveryStatus"
+	OpGetBalance       = "getBalance"
+	OpGetLines         = "getLines"
+	OpGetPatternDetail = "getPatternDetail"
+	OpGetInbox         = "getInbox"
+)
+
+// Call describes a single API call as it passes through the middleware chain
+type Call struct {
+	Operation string
+	Method    string
+	Endpoint  string
+
+	// Request is the typed request (e.g. models.SMSRequest), nil for calls without a body
+	Request interface{}
+	// Payload is the encoded JSON body sent to the API
+	Payload []byte
+	// Header holds extra request headers; they override the client's defaults
+	Header http.Header
+
+	// Response points at the typed response (e.g. *models.SMSResponse). It is
+	// filled in once the next handler returns without error.
+	Response interface{}
+	// ResponseBody is the raw response body of a successful call
+	ResponseBody []byte
+	// StatusCode is the HTTP status of the last response, zero when none was received
+	StatusCode int
+	// Cached is set when the response was served from the idempotency store
+	Cached bool
+}
+
+// RecipientCount returns the number of recipients of a send call
+func (c *Call) RecipientCount() (int, bool) {
+	switch req := c.Request.(type) {
+	case models.SMSRequest:
+		return len(req.Recipients), true
+	case models.PatternRequest:
+		return len(req.Recipients), true
+	case models.OTPRequest:
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// RequestCode returns the request code of a successful send call
+func (c *Call) RequestCode() string {
+	switch resp := c.Response.(type) {
+	case *models.SMSResponse:
+		return resp.Data.RequestCode
+	case *models.PatternResponse:
+		return resp.Data.RequestCode
+	case *models.OTPResponse:
+		return resp.Data.RequestCode
+	default:
+		return ""
+	}
+}
+
+// SmsItems returns the SMS items of a successful send or delivery status call
+func (c *Call) SmsItems() []models.SmsItemInfo {
+	switch resp := c.Response.(type) {
+	case *models.SMSResponse:
+		return resp.Data.SmsItems
+	case *models.PatternResponse:
+		return resp.Data.SmsItems
+	case *models.OTPResponse:
+		return resp.Data.SmsItems
+	case *models.DeliveryStatusResponse:
+		return resp.Data.SmsItems
+	default:
+		return nil
+	}
+}
+
+// Handler performs an API call
+type Handler func(ctx context.Context, call *Call) error
+
+// Middleware wraps a Handler to observe or change calls
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middleware around every API call. The first middleware
+// given is the outermost one.
+func WithMiddleware(middleware ...Middleware) Option {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// buildHandler chains the configured middleware around execute
+func (c *Client) buildHandler() Handler {
+	handler := c.execute
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		handler = c.middleware[i](handler)
+	}
+	return handler
+}
+
+// invoke runs a call through the middleware chain, decoding the response into response
+func (c *Client) invoke(ctx context.Context, operation, method, endpoint string, request, response interface{}) error {
+	call := &Call{
+		Operation: operation,
+		Method:    method,
+		Endpoint:  endpoint,
+		Request:   request,
+		Header:    make(http.Header),
+		Response:  response,
+	}
+
+	if request != nil {
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("failed to encode payload: %w", err)
+		}
+		call.Payload = payload
+	}
+
+	return c.handler(ctx, call)
+}
+
+// execute is the innermost handler: it serves idempotent sends from the
+// store or performs the HTTP request, then decodes the response
+func (c *Client) execute(ctx context.Context, call *Call) error {
+	key, hash := "", ""
+	if endpointClassOf(call.Endpoint) == EndpointSend {
+		key = c.idempotencyKey(ctx, call.Endpoint, call.Payload)
+	}
+
+	if key != "" {
+		hash = payloadHash(call.Endpoint, call.Payload)
+		done, err := c.reserveIdempotencyKey(ctx, key, hash, call)
+		if err != nil || done {
+			return err
+		}
+	}
+
+	resp, err := c.doRequest(ctx, call.Method, call.Endpoint, call.Payload, call.Header)
+	if err != nil {
+		var apiErr *errors.APIError
+		if stderrors.As(err, &apiErr) {
+			call.StatusCode = apiErr.StatusCode
+		}
+		if key != "" {
+			c.settleIdempotencyKey(key, hash, err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	call.StatusCode = resp.StatusCode
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		if key != "" {
+			c.settleIdempotencyKey(key, hash, err)
+		}
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if key != "" {
+		record := IdempotencyRecord{State: IdempotencyDone, PayloadHash: hash, Response: body}
+		if err := c.idempotency.store.Set(ctx, key, record, c.idempotency.window); err != nil {
+			return fmt.Errorf("failed to write idempotency store: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(body, call.Response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	call.ResponseBody = body
+
+	return nil
+}
+
+// reserveIdempotencyKey marks key as in flight before its send. When the key
+// was already used, it serves the cached response and reports done, or returns
+// why the send must not be made.
+func (c *Client) reserveIdempotencyKey(ctx context.Context, key, hash string, call *Call) (bool, error) {
+	existing, reserved, err := c.idempotency.store.Reserve(ctx, key, IdempotencyRecord{State: IdempotencyInFlight, PayloadHash: hash}, c.idempotency.window)
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	if reserved {
+		return false, nil
+	}
+
+	switch {
+	case existing.PayloadHash != hash:
+		return false, fmt.Errorf("%w: %s", errors.ErrIdempotencyKeyReused, key)
+	case existing.State == IdempotencyInFlight:
+		return false, fmt.Errorf("%w: %s", errors.ErrIdempotencyInFlight, key)
+	case existing.State != IdempotencyDone:
+		return false, fmt.Errorf("%w: %s", errors.ErrIdempotencyOutcomeUnknown, key)
+	}
+
+	if err := json.Unmarshal(existing.Response, call.Response); err != nil {
+		return false, fmt.Errorf("failed to decode cached response: %w", err)
+	}
+	call.ResponseBody = existing.Response
+	call.Cached = true
+	return true, nil
+}
+
+// settleIdempotencyKey records a failed send. The key is released when the API
+// certainly did not send anything, so the send can be made again; otherwise it
+// stays blocked because the message may have been delivered.
+func (c *Client) settleIdempotencyKey(key, hash string, sendErr error) {
+	// The caller's context may be done, and the record must still be written
+	ctx := context.Background()
+
+	var apiErr *errors.APIError
+	var notSent *notSentError
+	if stderrors.As(sendErr, &notSent) || (stderrors.As(sendErr, &apiErr) && apiErr.StatusCode < 500) {
+		c.idempotency.store.Delete(ctx, key)
+		return
+	}
+
+	c.idempotency.store.Set(ctx, key, IdempotencyRecord{State: IdempotencyUnknown, PayloadHash: hash}, c.idempotency.window)
+}
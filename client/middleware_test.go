@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+func TestMiddlewareOrderAndCall(t *testing.T) {
+	var gotHeader http.Header
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		gotHeader = r.Header.Clone()
+		w.Write([]byte(`{"data":{"requestCode":"rc-1"}}`))
+	})
+
+	var order []string
+	var seen *Call
+	var seenErr error
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, call *Call) error {
+				order = append(order, name+" before")
+				err := next(ctx, call)
+				order = append(order, name+" after")
+				return err
+			}
+		}
+	}
+	inspect := func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			call.Header.Set("X-Trace", "abc")
+			call.Header.Set("Accept", "application/json")
+			seenErr = next(ctx, call)
+			seen = call
+			return seenErr
+		}
+	}
+
+	c := New("key", WithBaseURL(srv.URL), WithMiddleware(trace("outer"), trace("inner")), WithMiddleware(inspect))
+	resp, err := c.SendOTP(context.Background(), otpRequest())
+	if err != nil {
+		t.Fatalf("SendOTP() error = %v", err)
+	}
+
+	want := []string{"outer before", "inner before", "inner after", "outer after"}
+	if len(order) != len(want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("order = %v, want %v", order, want)
+		}
+	}
+
+	if seen.Operation != OpSendOTP || seen.Method != http.MethodPost || seen.Endpoint != "send/otp" {
+		t.Errorf("call = %s %s %s, want sendOtp POST send/otp", seen.Operation, seen.Method, seen.Endpoint)
+	}
+	var payload models.OTPRequest
+	if err := json.Unmarshal(seen.Payload, &payload); err != nil || payload != otpRequest() {
+		t.Errorf("Payload = %s (%v), want the encoded request", seen.Payload, err)
+	}
+	if seen.Response != resp || seen.RequestCode() != "rc-1" || seenErr != nil || seen.StatusCode != http.StatusOK {
+		t.Errorf("call response = %v, code %q, err %v, status %d", seen.Response, seen.RequestCode(), seenErr, seen.StatusCode)
+	}
+
+	if got := gotHeader.Get("X-Trace"); got != "abc" {
+		t.Errorf("X-Trace header = %q, want abc", got)
+	}
+	if got := gotHeader.Values("Accept"); len(got) != 1 || got[0] != "application/json" {
+		t.Errorf("Accept header = %q, want the middleware override", got)
+	}
+	if got := gotHeader.Get("Authorization"); got != "Bearer key" {
+		t.Errorf("Authorization header = %q, want the client default", got)
+	}
+}
+
+func TestMiddlewareSeesErrors(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"meta":{"code":"1041","errorMessage":"invalid recipient"}}`))
+	})
+
+	var seen *Call
+	var seenErr error
+	c := New("key", WithBaseURL(srv.URL), WithMiddleware(func(next Handler) Handler {
+		return func(ctx context.Context, call *Call) error {
+			seenErr = next(ctx, call)
+			seen = call
+			return seenErr
+		}
+	}))
+	_, err := c.SendOTP(context.Background(), otpRequest())
+
+	var apiErr *errors.APIError
+	if !errors.As(seenErr, &apiErr) || apiErr.Code != "1041" || err != seenErr {
+		t.Fatalf("middleware error = %v, returned %v, want the same APIError", seenErr, err)
+	}
+	if seen.StatusCode != http.StatusBadRequest || seen.RequestCode() != "" {
+		t.Errorf("call status = %d, request code %q", seen.StatusCode, seen.RequestCode())
+	}
+}
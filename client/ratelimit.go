@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EndpointClass groups endpoints that share a rate limit budget
+type EndpointClass string
+
+const (
+	EndpointSend    EndpointClass = "send"    // send/*
+	EndpointStatus  EndpointClass = "status"  // send-requests/*
+	EndpointAccount EndpointClass = "account" // account/*
+	EndpointPattern EndpointClass = "pattern" // get/pattern/*
+)
+
+// RateLimit is a token-bucket budget: Rate tokens are added per second, up to Burst
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimitState is a snapshot of a single endpoint class budget
+type RateLimitState struct {
+	Rate   float64
+	Burst  int
+	Tokens float64 // tokens currently available
+}
+
+// WithRateLimit throttles outbound calls with a separate token bucket per
+// endpoint class. Calls block until a token is available or their context is
+// done. Classes missing from limits are not throttled.
+func WithRateLimit(limits map[EndpointClass]RateLimit) Option {
+	return func(c *Client) {
+		buckets := make(map[EndpointClass]*tokenBucket, len(limits))
+		for class, limit := range limits {
+			if limit.Rate <= 0 {
+				continue
+			}
+			buckets[class] = newTokenBucket(limit)
+		}
+		c.limiter = &rateLimiter{buckets: buckets}
+	}
+}
+
+// RateLimitState returns the current state of every configured budget
+func (c *Client) RateLimitState() map[EndpointClass]RateLimitState {
+	if c.limiter == nil {
+		return nil
+	}
+
+	states := make(map[EndpointClass]RateLimitState, len(c.limiter.buckets))
+	for class, bucket := range c.limiter.buckets {
+		states[class] = bucket.state()
+	}
+	return states
+}
+
+// endpointClassOf maps an endpoint path to its rate limit class
+func endpointClassOf(endpoint string) EndpointClass {
+	switch {
+	case strings.HasPrefix(endpoint, "send/"):
+		return EndpointSend
+	case strings.HasPrefix(endpoint, "send-requests/"):
+		return EndpointStatus
+	case strings.HasPrefix(endpoint, "account/"):
+		return EndpointAccount
+	case strings.HasPrefix(endpoint, "get/pattern"):
+		return EndpointPattern
+	default:
+		return EndpointClass(endpoint)
+	}
+}
+
+type rateLimiter struct {
+	buckets map[EndpointClass]*tokenBucket
+}
+
+// wait blocks until the endpoint's budget has a token or ctx is done
+func (l *rateLimiter) wait(ctx context.Context, endpoint string) error {
+	if l == nil {
+		return nil
+	}
+
+	bucket, ok := l.buckets[endpointClassOf(endpoint)]
+	if !ok {
+		return nil
+	}
+	return bucket.wait(ctx)
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: limit.Rate, burst: burst, tokens: burst, last: time.Now()}
+}
+
+// refill adds the tokens earned since the last refill; mu must be held
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		b.refill(time.Now())
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+func (b *tokenBucket) state() RateLimitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	return RateLimitState{Rate: b.rate, Burst: int(b.burst), Tokens: b.tokens}
+}
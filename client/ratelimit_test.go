@@ -0,0 +1,96 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimitBlocksUntilTokenIsAvailable(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithRateLimit(map[EndpointClass]RateLimit{
+		EndpointAccount: {Rate: 20, Burst: 1},
+	}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetAccountBalance(context.Background()); err != nil {
+			t.Fatalf("GetAccountBalance() error = %v", err)
+		}
+	}
+	// The burst covers the first call; the other two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 calls took %v, want at least 100ms", elapsed)
+	}
+}
+
+func TestRateLimitWaitIsCanceledWithContext(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithRateLimit(map[EndpointClass]RateLimit{
+		EndpointAccount: {Rate: 0.1, Burst: 1},
+	}))
+	if _, err := c.GetAccountBalance(context.Background()); err != nil {
+		t.Fatalf("GetAccountBalance() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := c.GetAccountBalance(ctx)
+	if !stderrors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("GetAccountBalance() error = %v, want context.DeadlineExceeded", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestRateLimitBudgetsAreSeparatePerClass(t *testing.T) {
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithRateLimit(map[EndpointClass]RateLimit{
+		EndpointAccount: {Rate: 0.1, Burst: 1},
+		EndpointStatus:  {Rate: 0.1, Burst: 1},
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := c.GetAccountBalance(ctx); err != nil {
+		t.Fatalf("GetAccountBalance() error = %v", err)
+	}
+	if _, err := c.GetDeliveryStatus(ctx, "rc1"); err != nil {
+		t.Fatalf("GetDeliveryStatus() error = %v", err)
+	}
+
+	state := c.RateLimitState()
+	if tokens := state[EndpointAccount].Tokens; tokens >= 1 {
+		t.Errorf("account tokens = %v, want less than 1", tokens)
+	}
+	if _, ok := state[EndpointSend]; ok {
+		t.Error("send class is throttled without a configured limit")
+	}
+}
+
+func TestEndpointClassOf(t *testing.T) {
+	tests := map[string]EndpointClass{
+		"send/sms":                EndpointSend,
+		"send-requests/status/rc": EndpointStatus,
+		"account/balance":         EndpointAccount,
+		"get/pattern/welcome":     EndpointPattern,
+	}
+	for endpoint, want := range tests {
+		if got := endpointClassOf(endpoint); got != want {
+			t.Errorf("endpointClassOf(%q) = %q, want %q", endpoint, got, want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/AryanHamedani/mediana-go-sdk/phone"
+)
+
+// WithRecipientNormalization makes SendSMS, SendPatternSMS and SendOTP
+// normalize recipients to 09XXXXXXXXX and drop duplicates before sending. A
+// send with an invalid recipient fails without calling the API, with an error
+// that wraps a *phone.InvalidNumberError for every invalid number.
+func WithRecipientNormalization() Option {
+	return func(c *Client) {
+		c.normalizeRecipients = true
+	}
+}
+
+// recipients normalizes and dedupes a send's recipients when enabled
+func (c *Client) recipients(recipients []string) ([]string, error) {
+	if !c.normalizeRecipients {
+		return recipients, nil
+	}
+
+	normalized, err := phone.NormalizeList(recipients)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipients: %w", err)
+	}
+	return normalized, nil
+}
+
+// recipient normalizes a single recipient when enabled
+func (c *Client) recipient(recipient string) (string, error) {
+	if !c.normalizeRecipients {
+		return recipient, nil
+	}
+
+	normalized, err := phone.Normalize(recipient)
+	if err != nil {
+		return "", fmt.Errorf("invalid recipient: %w", err)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+	"github.com/AryanHamedani/mediana-go-sdk/phone"
+)
+
+func TestRecipientNormalization(t *testing.T) {
+	var sms models.SMSRequest
+	var otp models.OTPRequest
+	srv, _ := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			json.NewDecoder(r.Body).Decode(&sms)
+		} else {
+			json.NewDecoder(r.Body).Decode(&otp)
+		}
+		w.Write([]byte(`{"data":{"requestCode":"rc-1"}}`))
+	})
+	c := New("key", WithBaseURL(srv.URL), WithRecipientNormalization())
+
+	_, err := c.SendSMS(context.Background(), models.SMSRequest{
+		Recipients:  []string{"+98 912 123 4567", "09121234567", "۰۹۳۵۱۲۳۴۵۶۷"},
+		MessageText: "hi",
+	})
+	if err != nil {
+		t.Fatalf("SendSMS() error = %v", err)
+	}
+	if want := []string{"09121234567", "09351234567"}; !reflect.DeepEqual(sms.Recipients, want) {
+		t.Errorf("sent recipients = %v, want %v", sms.Recipients, want)
+	}
+
+	req := otpRequest()
+	req.Recipient = "0098 912 123 4567"
+	if _, err := c.SendOTP(context.Background(), req); err != nil {
+		t.Fatalf("SendOTP() error = %v", err)
+	}
+	if otp.Recipient != "09121234567" {
+		t.Errorf("sent recipient = %q, want 09121234567", otp.Recipient)
+	}
+}
+
+func TestRecipientNormalizationRejectsInvalidNumbers(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{"requestCode":"rc-1"}}`))
+	})
+	c := New("key", WithBaseURL(srv.URL), WithRecipientNormalization())
+
+	_, err := c.SendSMS(context.Background(), models.SMSRequest{
+		Recipients:  []string{"09121234567", "02112345678"},
+		MessageText: "hi",
+	})
+	var invalid *phone.InvalidNumberError
+	if !errors.As(err, &invalid) || invalid.Number != "02112345678" {
+		t.Errorf("SendSMS() error = %v, want an InvalidNumberError for the landline", err)
+	}
+
+	req := otpRequest()
+	req.Recipient = "+14155552671"
+	if _, err := c.SendOTP(context.Background(), req); !errors.As(err, &invalid) || invalid.Reason != phone.ReasonForeign {
+		t.Errorf("SendOTP() error = %v, want an InvalidNumberError for the foreign number", err)
+	}
+
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Errorf("API calls = %d, want 0", got)
+	}
+}
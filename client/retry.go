@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how failed API calls are retried
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts
+	MaxBackoff time.Duration
+	// MaxRetryAfter is the longest Retry-After the client waits for; a longer
+	// one ends the retries. Defaults to MaxBackoff, or 30 seconds without one.
+	MaxRetryAfter time.Duration
+	// Multiplier grows the delay after every attempt
+	Multiplier float64
+	// Jitter is the fraction (0 to 1) of every delay that is randomized
+	Jitter float64
+	// RetryNonIdempotent allows send requests (POST) to be replayed. A replayed
+	// send may deliver the same message twice, so this is off by default.
+	RetryNonIdempotent bool
+	// OnAttempt is called after every attempt, whether or not it is retried
+	OnAttempt func(RetryAttempt)
+}
+
+// RetryAttempt describes a single attempt made by the client
+type RetryAttempt struct {
+	Attempt    int
+	Method     string
+	Endpoint   string
+	StatusCode int           // zero when the request failed before a response
+	Err        error         // transport error, if any
+	Retrying   bool          // whether another attempt follows
+	Delay      time.Duration // wait before the next attempt
+}
+
+// DefaultRetryPolicy returns a policy with three attempts and a capped
+// exponential backoff starting at 200ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// WithRetryPolicy enables automatic retries of network errors, 5xx and 429 responses
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// maxAttempts returns how many attempts are allowed for the given method
+func (p *RetryPolicy) maxAttempts(method string) int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	if method != http.MethodGet && !p.RetryNonIdempotent {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the given retry (1 for the first retry)
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		delay -= delay * jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// retryAfterLimit returns the longest Retry-After worth waiting for
+func (p *RetryPolicy) retryAfterLimit() time.Duration {
+	switch {
+	case p.MaxRetryAfter > 0:
+		return p.MaxRetryAfter
+	case p.MaxBackoff > 0:
+		return p.MaxBackoff
+	default:
+		return 30 * time.Second
+	}
+}
+
+// isRetryableStatus reports whether a response status is worth another attempt
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// fitsDeadline reports whether waiting for delay still leaves the context alive
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return true
+	}
+	return time.Now().Add(delay).Before(deadline)
+}
+
+// sleep waits for the delay or until the context is done
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+)
+
+func fastRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Multiplier: 2}
+}
+
+func TestRetryRecoversFromServerErrors(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"data":{"Balance":42}}`))
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy()))
+	resp, err := c.GetAccountBalance(context.Background())
+	if err != nil {
+		t.Fatalf("GetAccountBalance() error = %v", err)
+	}
+	if resp.Data.Balance != 42 {
+		t.Errorf("Balance = %d, want 42", resp.Data.Balance)
+	}
+	if got := atomic.LoadInt32(calls); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRetryDoesNotReplaySends(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy()))
+	if _, err := c.SendOTP(context.Background(), otpRequest()); err == nil {
+		t.Fatal("SendOTP() error = nil, want an error")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		if call == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	})
+
+	policy := fastRetryPolicy()
+	policy.MaxRetryAfter = 2 * time.Second
+	var delays []time.Duration
+	policy.OnAttempt = func(a RetryAttempt) {
+		if a.Retrying {
+			delays = append(delays, a.Delay)
+		}
+	}
+
+	c := New("key", WithBaseURL(srv.URL), WithRetryPolicy(policy))
+	if _, err := c.GetAccountBalance(context.Background()); err != nil {
+		t.Fatalf("GetAccountBalance() error = %v", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+	if len(delays) != 1 || delays[0] != time.Second {
+		t.Errorf("delays = %v, want [1s]", delays)
+	}
+}
+
+func TestRetryStopsOnLongRetryAfter(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	c := New("key", WithBaseURL(srv.URL), WithRetryPolicy(fastRetryPolicy()))
+	start := time.Now()
+	_, err := c.GetAccountBalance(context.Background())
+
+	var apiErr *errors.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("error = %v, want a 429 API error", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("call took %v, want no wait", elapsed)
+	}
+}
+
+func TestRetryStopsBeforeDeadline(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	policy := fastRetryPolicy()
+	policy.InitialBackoff = time.Second
+	policy.MaxBackoff = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	c := New("key", WithBaseURL(srv.URL), WithRetryPolicy(policy))
+	if _, err := c.GetAccountBalance(ctx); err == nil {
+		t.Fatal("GetAccountBalance() error = nil, want an error")
+	}
+	if got := atomic.LoadInt32(calls); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+	if ctx.Err() != nil {
+		t.Error("client waited until the deadline instead of giving up")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.header)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
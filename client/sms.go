@@ -2,52 +2,64 @@ package client
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"net/url"
 
 	"github.com/AryanHamedani/mediana-go-sdk/models"
+	"github.com/AryanHamedani/mediana-go-sdk/segment"
 )
 
+// WithMaxSegments makes SendSMS refuse message texts that need more than
+// maxSegments billable parts with a *segment.TooLongError, before calling the API
+func WithMaxSegments(maxSegments int) Option {
+	return func(c *Client) {
+		c.maxSegments = maxSegments
+	}
+}
+
 func (c *Client) SendSMS(ctx context.Context, req models.SMSRequest) (*models.SMSResponse, error) {
-	resp, err := c.doRequest(ctx, "POST", "send/sms", req)
-	if err != nil {
+	var err error
+	if req.Recipients, err = c.recipients(req.Recipients); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+
+	if c.maxSegments > 0 {
+		if err := segment.Check(req.MessageText, c.maxSegments); err != nil {
+			return nil, err
+		}
+	}
 
 	var response models.SMSResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.invoke(ctx, OpSendSMS, "POST", "send/sms", req, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
 }
 
 func (c *Client) SendPatternSMS(ctx context.Context, req models.PatternRequest) (*models.PatternResponse, error) {
-	resp, err := c.doRequest(ctx, "POST", "send/pattern", req)
-	if err != nil {
+	var err error
+	if req.Recipients, err = c.recipients(req.Recipients); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	var response models.PatternResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.invoke(ctx, OpSendPattern, "POST", "send/pattern", req, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
 }
 
 func (c *Client) SendOTP(ctx context.Context, req models.OTPRequest) (*models.OTPResponse, error) {
-	resp, err := c.doRequest(ctx, "POST", "send/otp", req)
-	if err != nil {
+	var err error
+	if req.Recipient, err = c.recipient(req.Recipient); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
 	var response models.OTPResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.invoke(ctx, OpSendOTP, "POST", "send/otp", req, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
@@ -56,15 +68,9 @@ func (c *Client) SendOTP(ctx context.Context, req models.OTPRequest) (*models.OT
 // GetDeliveryStatus retrieves the delivery status of a message by request ID
 func (c *Client) GetDeliveryStatus(ctx context.Context, requestID string) (*models.DeliveryStatusResponse, error) {
 	endpoint := fmt.Sprintf("send-requests/status/%s", requestID)
-	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var response models.DeliveryStatusResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.invoke(ctx, OpDeliveryStatus, "GET", endpoint, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
@@ -72,15 +78,9 @@ func (c *Client) GetDeliveryStatus(ctx context.Context, requestID string) (*mode
 
 // GetAccountBalance retrieves the current balance of the account
 func (c *Client) GetAccountBalance(ctx context.Context) (*models.BalanceResponse, error) {
-	resp, err := c.doRequest(ctx, "GET", "account/balance", nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var response models.BalanceResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.invoke(ctx, OpGetBalance, "GET", "account/balance", nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
@@ -88,15 +88,9 @@ func (c *Client) GetAccountBalance(ctx context.Context) (*models.BalanceResponse
 
 // GetSendingLines retrieves the available sending lines for the account
 func (c *Client) GetSendingLines(ctx context.Context) (*models.LinesResponse, error) {
-	resp, err := c.doRequest(ctx, "GET", "account/lines", nil)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
 	var response models.LinesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := c.invoke(ctx, OpGetLines, "GET", "account/lines", nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
@@ -105,15 +99,24 @@ func (c *Client) GetSendingLines(ctx context.Context) (*models.LinesResponse, er
 // GetPatternDetail retrieves details of a specific pattern
 func (c *Client) GetPatternDetail(ctx context.Context, patternCode string) (*models.PatternDetailResponse, error) {
 	endpoint := fmt.Sprintf("get/pattern/%s", patternCode)
-	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
-	if err != nil {
+	var response models.PatternDetailResponse
+	if err := c.invoke(ctx, OpGetPatternDetail, "GET", endpoint, nil, &response); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
 
-	var response models.PatternDetailResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	return &response, nil
+}
+
+// GetInbox retrieves the messages received on the account's lines with the given
+// status; an empty status defaults to models.InboxStatusNew
+func (c *Client) GetInbox(ctx context.Context, status string) (*models.InboxResponse, error) {
+	if status == "" {
+		status = models.InboxStatusNew
+	}
+	endpoint := "send-requests/inbox?" + url.Values{"Status": {status}}.Encode()
+	var response models.InboxResponse
+	if err := c.invoke(ctx, OpGetInbox, "GET", endpoint, nil, &response); err != nil {
+		return nil, err
 	}
 
 	return &response, nil
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+	"github.com/AryanHamedani/mediana-go-sdk/segment"
+)
+
+func TestMaxSegmentsRejectsLongText(t *testing.T) {
+	srv, calls := newTestServer(t, func(w http.ResponseWriter, r *http.Request, call int) {
+		w.Write([]byte(`{"data":{"requestCode":"rc-1"}}`))
+	})
+	c := New("key", WithBaseURL(srv.URL), WithMaxSegments(1))
+	req := models.SMSRequest{Recipients: []string{"09121234567"}, MessageText: strings.Repeat("س", 71)}
+
+	_, err := c.SendSMS(context.Background(), req)
+	var tooLong *segment.TooLongError
+	if !errors.As(err, &tooLong) || tooLong.Info.Segments != 2 {
+		t.Fatalf("SendSMS() error = %v, want a TooLongError for 2 parts", err)
+	}
+	if got := atomic.LoadInt32(calls); got != 0 {
+		t.Errorf("API calls = %d, want 0", got)
+	}
+
+	req.MessageText = strings.Repeat("س", 70)
+	if _, err := c.SendSMS(context.Background(), req); err != nil {
+		t.Fatalf("SendSMS() with a single part error = %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+// Package cost estimates what a send will cost and checks it against the
+// account balance before the first message goes out, instead of failing with
+// error 1042 halfway through a campaign.
+package cost
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+	"github.com/AryanHamedani/mediana-go-sdk/segment"
+)
+
+// LineType is the kind of line a message is sent from, as far as pricing goes
+type LineType string
+
+const (
+	LineShared    LineType = "shared"
+	LineDedicated LineType = "dedicated"
+)
+
+// LineTypeOf returns the pricing line type of a sending line
+func LineTypeOf(line models.LineInfo) LineType {
+	if line.IsDedicated {
+		return LineDedicated
+	}
+	return LineShared
+}
+
+// PriceKey selects a price in a PriceTable
+type PriceKey struct {
+	MessageType models.MessageType
+	LineType    LineType
+}
+
+// PriceTable holds the price of a single message part, in the unit of the
+// account balance, per message type and line type
+type PriceTable map[PriceKey]int64
+
+// Message is a send to estimate
+type Message struct {
+	// Text is the message text; it decides the number of parts unless Segments is set
+	Text string
+	// Segments overrides the part count, for sends such as patterns whose final text is not known
+	Segments int
+	// Recipients is the number of recipients
+	Recipients  int
+	MessageType models.MessageType
+	LineType    LineType
+}
+
+// ItemEstimate is the estimated cost of a single message
+type ItemEstimate struct {
+	Message Message
+	// Segments is the number of parts every recipient receives
+	Segments        int
+	PricePerSegment int64
+	Cost            int64
+}
+
+// Report is the estimated cost of a send, to be shown to operators for approval
+type Report struct {
+	Items []ItemEstimate
+	// Total is the estimated cost of every item
+	Total int64
+	// Balance is the account balance the total was checked against
+	Balance int64
+	// Shortfall is how much the total exceeds the balance, or zero
+	Shortfall int64
+}
+
+// Sufficient reports whether the balance covers the estimated cost
+func (r *Report) Sufficient() bool {
+	return r.Shortfall == 0
+}
+
+// InsufficientBalanceError is returned by Check when the balance does not
+// cover the estimated cost. It matches errors.ErrInsufficientBalance.
+type InsufficientBalanceError struct {
+	Report *Report
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("estimated cost %d exceeds balance %d by %d", e.Report.Total, e.Report.Balance, e.Report.Shortfall)
+}
+
+// Unwrap makes the error match errors.ErrInsufficientBalance
+func (e *InsufficientBalanceError) Unwrap() error {
+	return errors.ErrInsufficientBalance
+}
+
+// BalanceGetter is the part of *client.Client the estimator uses
+type BalanceGetter interface {
+	GetAccountBalance(ctx context.Context) (*models.BalanceResponse, error)
+}
+
+// Estimator prices sends and checks them against the account balance
+type Estimator struct {
+	client BalanceGetter
+	prices PriceTable
+	onWarn func(*Report)
+}
+
+// Option configures an Estimator
+type Option func(*Estimator)
+
+// WithWarnOnly makes Check call warn instead of failing when the balance does not cover a send
+func WithWarnOnly(warn func(*Report)) Option {
+	return func(e *Estimator) {
+		e.onWarn = warn
+	}
+}
+
+// NewEstimator creates an estimator with the given price table
+func NewEstimator(client BalanceGetter, prices PriceTable, options ...Option) *Estimator {
+	e := &Estimator{client: client, prices: prices}
+
+	for _, opt := range options {
+		opt(e)
+	}
+
+	return e
+}
+
+// Estimate prices messages without looking at the balance
+func (e *Estimator) Estimate(messages ...Message) (*Report, error) {
+	report := &Report{}
+	for _, msg := range messages {
+		key := PriceKey{MessageType: msg.MessageType, LineType: msg.LineType}
+		price, ok := e.prices[key]
+		if !ok {
+			return nil, fmt.Errorf("no price for %s messages on %s lines", msg.MessageType, msg.LineType)
+		}
+
+		segments := msg.Segments
+		if segments <= 0 {
+			segments = segment.Calculate(msg.Text).Segments
+		}
+
+		item := ItemEstimate{
+			Message:         msg,
+			Segments:        segments,
+			PricePerSegment: price,
+			Cost:            int64(segments) * int64(msg.Recipients) * price,
+		}
+		report.Items = append(report.Items, item)
+		report.Total += item.Cost
+	}
+	return report, nil
+}
+
+// Check prices messages and compares the total with GetAccountBalance. When
+// the balance falls short it returns the report with an
+// *InsufficientBalanceError, or calls the WithWarnOnly handler and returns no
+// error.
+func (e *Estimator) Check(ctx context.Context, messages ...Message) (*Report, error) {
+	report, err := e.Estimate(messages...)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.GetAccountBalance(ctx)
+	if err != nil {
+		return report, fmt.Errorf("failed to get account balance: %w", err)
+	}
+
+	report.Balance = int64(resp.Data.Balance)
+	if report.Total > report.Balance {
+		report.Shortfall = report.Total - report.Balance
+	}
+
+	if report.Sufficient() {
+		return report, nil
+	}
+	if e.onWarn != nil {
+		e.onWarn(report)
+		return report, nil
+	}
+	return report, &InsufficientBalanceError{Report: report}
+}
@@ -0,0 +1,96 @@
+package cost
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+type fakeBalance int
+
+func (b fakeBalance) GetAccountBalance(ctx context.Context) (*models.BalanceResponse, error) {
+	resp := &models.BalanceResponse{}
+	resp.Data.Balance = int(b)
+	return resp, nil
+}
+
+var testPrices = PriceTable{
+	{MessageType: models.MessageTypeService, LineType: LineShared}:       100,
+	{MessageType: models.MessageTypeService, LineType: LineDedicated}:    150,
+	{MessageType: models.MessageTypeAdvertisement, LineType: LineShared}: 80,
+}
+
+func TestEstimate(t *testing.T) {
+	e := NewEstimator(nil, testPrices)
+	report, err := e.Estimate(
+		// 71 Persian letters take two UCS-2 parts
+		Message{Text: strings.Repeat("س", 71), Recipients: 3, MessageType: models.MessageTypeService, LineType: LineShared},
+		Message{Text: "hello", Recipients: 10, MessageType: models.MessageTypeAdvertisement, LineType: LineShared},
+		// A pattern whose final text is not known is priced by its Segments
+		Message{Segments: 4, Recipients: 2, MessageType: models.MessageTypeService, LineType: LineDedicated},
+	)
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+
+	want := []struct {
+		segments int
+		cost     int64
+	}{{2, 2 * 3 * 100}, {1, 1 * 10 * 80}, {4, 4 * 2 * 150}}
+	if len(report.Items) != len(want) {
+		t.Fatalf("items = %d, want %d", len(report.Items), len(want))
+	}
+	for i, w := range want {
+		if item := report.Items[i]; item.Segments != w.segments || item.Cost != w.cost {
+			t.Errorf("item %d = %d segments costing %d, want %d costing %d", i, item.Segments, item.Cost, w.segments, w.cost)
+		}
+	}
+	if report.Total != 600+800+1200 {
+		t.Errorf("Total = %d, want 2600", report.Total)
+	}
+}
+
+func TestEstimateWithoutPrice(t *testing.T) {
+	e := NewEstimator(nil, testPrices)
+	_, err := e.Estimate(Message{Text: "hi", Recipients: 1, MessageType: models.MessageTypeAdvertisement, LineType: LineDedicated})
+	if err == nil || !strings.Contains(err.Error(), "no price") {
+		t.Fatalf("Estimate() error = %v, want a missing price error", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	msg := Message{Text: "hi", Recipients: 5, MessageType: models.MessageTypeService, LineType: LineShared} // costs 500
+
+	report, err := NewEstimator(fakeBalance(500), testPrices).Check(context.Background(), msg)
+	if err != nil || !report.Sufficient() || report.Balance != 500 {
+		t.Fatalf("Check() with enough balance = %+v, %v", report, err)
+	}
+
+	report, err = NewEstimator(fakeBalance(320), testPrices).Check(context.Background(), msg)
+	var balanceErr *InsufficientBalanceError
+	if !errors.As(err, &balanceErr) || balanceErr.Report != report {
+		t.Fatalf("Check() error = %v, want an InsufficientBalanceError with the report", err)
+	}
+	if !errors.Is(err, errors.ErrInsufficientBalance) || !errors.IsQuota(err) {
+		t.Errorf("error %v does not match ErrInsufficientBalance", err)
+	}
+	if report.Shortfall != 180 || report.Sufficient() {
+		t.Errorf("Shortfall = %d, want 180", report.Shortfall)
+	}
+}
+
+func TestCheckWarnOnly(t *testing.T) {
+	var warned *Report
+	e := NewEstimator(fakeBalance(100), testPrices, WithWarnOnly(func(r *Report) { warned = r }))
+
+	report, err := e.Check(context.Background(), Message{Text: "hi", Recipients: 5, MessageType: models.MessageTypeService, LineType: LineShared})
+	if err != nil {
+		t.Fatalf("Check() error = %v, want none in warn-only mode", err)
+	}
+	if warned != report || report.Shortfall != 400 {
+		t.Errorf("warned with %+v, want the report with a 400 shortfall", warned)
+	}
+}
@@ -0,0 +1,174 @@
+package errors
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// Category tells a caller what to do with a failed request
+type Category int
+
+const (
+	// CategoryUnknown is used for errors that cannot be classified, e.g. a cancelled context
+	CategoryUnknown Category = iota
+	// CategoryRetryable errors are transient and the request can be retried right away with backoff
+	CategoryRetryable
+	// CategoryRetryLater errors are transient but the request should wait, e.g. a line that is not usable at this time of day
+	CategoryRetryLater
+	// CategoryPermanent errors fail the same way every time, e.g. invalid or blacklisted recipients
+	CategoryPermanent
+	// CategoryAuth errors come from the API key or the account's plan and verification
+	CategoryAuth
+	// CategoryQuota errors come from the account running out of balance or plan
+	CategoryQuota
+)
+
+func (c Category) String() string {
+	switch c {
+	case CategoryRetryable:
+		return "retryable"
+	case CategoryRetryLater:
+		return "retry-later"
+	case CategoryPermanent:
+		return "permanent"
+	case CategoryAuth:
+		return "auth"
+	case CategoryQuota:
+		return "quota"
+	default:
+		return "unknown"
+	}
+}
+
+var errorCodeCategories = map[ErrorCode]Category{
+	ErrUnknown:                 CategoryRetryable,
+	ErrNoActivePlan:            CategoryQuota,
+	ErrPlanNoAPI:               CategoryAuth,
+	ErrPlanNoPattern:           CategoryAuth,
+	ErrPlanNoDedicatedLine:     CategoryAuth,
+	ErrInvalidReceiver:         CategoryPermanent,
+	ErrInsufficientBalance:     CategoryQuota,
+	ErrMaxReceiversExceeded:    CategoryPermanent,
+	ErrInvalidSmsID:            CategoryPermanent,
+	ErrInvalidRequestCode:      CategoryPermanent,
+	ErrInvalidParameters:       CategoryPermanent,
+	ErrBlacklisted:             CategoryPermanent,
+	ErrWebEngageDisabled:       CategoryAuth,
+	ErrCampaignExpired:         CategoryPermanent,
+	ErrNoActiveLine:            CategoryPermanent,
+	ErrLineNotUsableNow:        CategoryRetryLater,
+	ErrPatternURLDetected:      CategoryPermanent,
+	ErrPatternRejected:         CategoryPermanent,
+	ErrPatternOtherNumber:      CategoryPermanent,
+	ErrEmptyMessageText:        CategoryPermanent,
+	ErrMessageRequestNotFound:  CategoryPermanent,
+	ErrEmptyPattern:            CategoryPermanent,
+	ErrPostalCodeNotVerified:   CategoryAuth,
+	ErrNationalCodeNotVerified: CategoryAuth,
+	ErrMobileNotVerified:       CategoryAuth,
+	ErrProfileNotCompleted:     CategoryAuth,
+	ErrReceiversNotFound:       CategoryPermanent,
+	ErrSendingNumberNotFound:   CategoryPermanent,
+	ErrSendingNumberExpired:    CategoryPermanent,
+}
+
+// categoryPrecedence orders categories from least to most severe; when a
+// response carries several codes the most severe one wins
+var categoryPrecedence = map[Category]int{
+	CategoryUnknown:    0,
+	CategoryRetryable:  1,
+	CategoryRetryLater: 2,
+	CategoryPermanent:  3,
+	CategoryQuota:      4,
+	CategoryAuth:       5,
+}
+
+// Category returns the category of the code; undocumented codes are unknown
+func (c ErrorCode) Category() Category {
+	return errorCodeCategories[c]
+}
+
+// PerRecipient reports whether the code rejects individual recipients rather
+// than the whole request, so the other recipients can still be sent to
+func (c ErrorCode) PerRecipient() bool {
+	return c == ErrInvalidReceiver || c == ErrBlacklisted
+}
+
+// Category classifies the error from its Mediana codes, falling back to the HTTP status
+func (e *APIError) Category() Category {
+	category := CategoryUnknown
+	for _, code := range e.ErrorCodes {
+		if c := code.Category(); categoryPrecedence[c] > categoryPrecedence[category] {
+			category = c
+		}
+	}
+	if category != CategoryUnknown {
+		return category
+	}
+
+	switch {
+	case e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden:
+		return CategoryAuth
+	case e.StatusCode == http.StatusPaymentRequired:
+		return CategoryQuota
+	case e.StatusCode == http.StatusTooManyRequests:
+		return CategoryRetryLater
+	case e.StatusCode >= 500:
+		return CategoryRetryable
+	case e.StatusCode >= 400:
+		return CategoryPermanent
+	default:
+		return CategoryUnknown
+	}
+}
+
+// Classify returns the category of any error returned by the client: API
+// errors, Mediana error codes, an open circuit breaker, timeouts and network
+// failures
+func Classify(err error) Category {
+	if err == nil {
+		return CategoryUnknown
+	}
+
+	var apiErr *APIError
+	var code ErrorCode
+	var netErr net.Error
+	switch {
+	case Is(err, ErrCircuitOpen):
+		return CategoryRetryLater
+	case As(err, &apiErr):
+		return apiErr.Category()
+	case As(err, &code):
+		return code.Category()
+	case Is(err, context.Canceled):
+		return CategoryUnknown
+	case Is(err, context.DeadlineExceeded):
+		return CategoryRetryable
+	case As(err, &netErr):
+		return CategoryRetryable
+	default:
+		return CategoryUnknown
+	}
+}
+
+// IsRetryable reports whether the request may succeed if sent again, now or later
+func IsRetryable(err error) bool {
+	category := Classify(err)
+	return category == CategoryRetryable || category == CategoryRetryLater
+}
+
+// IsPermanent reports whether sending the same request again will fail the same way
+func IsPermanent(err error) bool {
+	return Classify(err) == CategoryPermanent
+}
+
+// IsAuth reports whether the error comes from the API key or the account's plan or verification
+func IsAuth(err error) bool {
+	return Classify(err) == CategoryAuth
+}
+
+// IsQuota reports whether the error comes from the account running out of balance or plan
+func IsQuota(err error) bool {
+	return Classify(err) == CategoryQuota
+}
@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestClassifyAPIErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want Category
+	}{
+		{"1042 balance", `{"meta":{"code":"1042"}}`, CategoryQuota},
+		{"1047 blacklisted", `{"meta":{"code":"1047"}}`, CategoryPermanent},
+		{"1062 line not usable now", `{"meta":{"code":"1062"}}`, CategoryRetryLater},
+		{"1021 unknown", `{"meta":{"code":"1021"}}`, CategoryRetryable},
+		{"field code", `{"meta":{"errors":[{"key":"recipients","errors":["bad"],"errorCode":1041}]}}`, CategoryPermanent},
+		{"most severe code wins", `{"meta":{"code":"1062","errors":[{"key":"recipients","errors":["bad"],"errorCode":1041},{"key":"balance","errors":["low"],"errorCode":1042}]}}`, CategoryQuota},
+		{"retry-later over retryable", `{"meta":{"code":"1021","errors":[{"key":"line","errors":["closed"],"errorCode":1062}]}}`, CategoryRetryLater},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ParseError(errorResponse(http.StatusBadRequest, tt.body))
+			if got := Classify(err); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyFallsBackToStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		want   Category
+	}{
+		{http.StatusUnauthorized, CategoryAuth},
+		{http.StatusForbidden, CategoryAuth},
+		{http.StatusPaymentRequired, CategoryQuota},
+		{http.StatusTooManyRequests, CategoryRetryLater},
+		{http.StatusBadGateway, CategoryRetryable},
+		{http.StatusBadRequest, CategoryPermanent},
+	}
+	for _, tt := range tests {
+		// An undocumented code does not decide the category either
+		err := ParseError(errorResponse(tt.status, `{"meta":{"code":"9999"}}`))
+		if got := Classify(err); got != tt.want {
+			t.Errorf("Classify(%d) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyOtherErrors(t *testing.T) {
+	transport := &url.Error{Op: "Post", URL: "https://api.mediana.ir", Err: stderrors.New("connection refused")}
+	tests := []struct {
+		name string
+		err  error
+		want Category
+	}{
+		{"nil", nil, CategoryUnknown},
+		{"circuit open", &CircuitOpenError{}, CategoryRetryLater},
+		{"code", fmt.Errorf("rejected: %w", ErrBlacklisted), CategoryPermanent},
+		{"wrapped API error", fmt.Errorf("send failed: %w", &APIError{StatusCode: 400, ErrorCodes: []ErrorCode{ErrInsufficientBalance}}), CategoryQuota},
+		{"url error", fmt.Errorf("failed to send request: %w", transport), CategoryRetryable},
+		{"deadline", fmt.Errorf("failed to send request: %w", &url.Error{Op: "Post", URL: "u", Err: context.DeadlineExceeded}), CategoryRetryable},
+		{"canceled", fmt.Errorf("failed to send request: %w", &url.Error{Op: "Post", URL: "u", Err: context.Canceled}), CategoryUnknown},
+		{"plain", stderrors.New("boom"), CategoryUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.want {
+				t.Errorf("Classify() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPerRecipient(t *testing.T) {
+	for code, want := range map[ErrorCode]bool{
+		ErrInvalidReceiver:     true,
+		ErrBlacklisted:         true,
+		ErrInsufficientBalance: false,
+		ErrLineNotUsableNow:    false,
+	} {
+		if got := code.PerRecipient(); got != want {
+			t.Errorf("%d.PerRecipient() = %v, want %v", int(code), got, want)
+		}
+	}
+}
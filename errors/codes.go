@@ -0,0 +1,116 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrorCode is a Mediana error code as documented in openapi.yaml. Every code
+// is an error, so the constants below work as sentinels with errors.Is:
+//
+//	if errors.Is(err, errors.ErrInsufficientBalance) { ... }
+type ErrorCode int
+
+const (
+	ErrUnknown                 ErrorCode = 1021 // Unknown error occurred
+	ErrNoActivePlan            ErrorCode = 1032 // No active plan found
+	ErrPlanNoAPI               ErrorCode = 1033 // Plan does not have API facility
+	ErrPlanNoPattern           ErrorCode = 1034 // Plan does not have pattern facility
+	ErrPlanNoDedicatedLine     ErrorCode = 1035 // Plan does not have a dedicated line facility
+	ErrInvalidReceiver         ErrorCode = 1041 // Invalid receiver in API request
+	ErrInsufficientBalance     ErrorCode = 1042 // Insufficient wallet balance
+	ErrMaxReceiversExceeded    ErrorCode = 1043 // Maximum number of receivers exceeded
+	ErrInvalidSmsID            ErrorCode = 1044 // Invalid SMS ID
+	ErrInvalidRequestCode      ErrorCode = 1045 // Invalid request code
+	ErrInvalidParameters       ErrorCode = 1046 // Invalid input parameters
+	ErrBlacklisted             ErrorCode = 1047 // Phone number is blacklisted
+	ErrWebEngageDisabled       ErrorCode = 1048 // WebEngage is not enabled
+	ErrCampaignExpired         ErrorCode = 1051 // Campaign has expired
+	ErrNoActiveLine            ErrorCode = 1061 // No active line found
+	ErrLineNotUsableNow        ErrorCode = 1062 // Line is not usable at this time of day
+	ErrPatternURLDetected      ErrorCode = 1071 // Pattern URL detected
+	ErrPatternRejected         ErrorCode = 1072 // Pattern rejected by admin
+	ErrPatternOtherNumber      ErrorCode = 1073 // Pattern belongs to another sending number
+	ErrEmptyMessageText        ErrorCode = 1074 // Message text is empty
+	ErrMessageRequestNotFound  ErrorCode = 1075 // Message request not found
+	ErrEmptyPattern            ErrorCode = 1076 // Pattern is empty
+	ErrPostalCodeNotVerified   ErrorCode = 1081 // Postal code not verified
+	ErrNationalCodeNotVerified ErrorCode = 1082 // National code not verified
+	ErrMobileNotVerified       ErrorCode = 1083 // Mobile number not verified
+	ErrProfileNotCompleted     ErrorCode = 1084 // Profile not completed
+	ErrReceiversNotFound       ErrorCode = 1093 // Receivers not found
+	ErrSendingNumberNotFound   ErrorCode = 1101 // Sending number not found
+	ErrSendingNumberExpired    ErrorCode = 1102 // Sending number has expired
+)
+
+var errorCodeDescriptions = map[ErrorCode]string{
+	ErrUnknown:                 "unknown error occurred",
+	ErrNoActivePlan:            "no active plan found",
+	ErrPlanNoAPI:               "plan does not have API facility",
+	ErrPlanNoPattern:           "plan does not have pattern facility",
+	ErrPlanNoDedicatedLine:     "plan does not have a dedicated line facility",
+	ErrInvalidReceiver:         "invalid receiver in API request",
+	ErrInsufficientBalance:     "insufficient wallet balance",
+	ErrMaxReceiversExceeded:    "maximum number of receivers exceeded",
+	ErrInvalidSmsID:            "invalid SMS ID",
+	ErrInvalidRequestCode:      "invalid request code",
+	ErrInvalidParameters:       "invalid input parameters",
+	ErrBlacklisted:             "phone number is blacklisted",
+	ErrWebEngageDisabled:       "WebEngage is not enabled",
+	ErrCampaignExpired:         "campaign has expired",
+	ErrNoActiveLine:            "no active line found",
+	ErrLineNotUsableNow:        "line is not usable at this time of day",
+	ErrPatternURLDetected:      "pattern URL detected",
+	ErrPatternRejected:         "pattern rejected by admin",
+	ErrPatternOtherNumber:      "pattern belongs to another sending number",
+	ErrEmptyMessageText:        "message text is empty",
+	ErrMessageRequestNotFound:  "message request not found",
+	ErrEmptyPattern:            "pattern is empty",
+	ErrPostalCodeNotVerified:   "postal code not verified",
+	ErrNationalCodeNotVerified: "national code not verified",
+	ErrMobileNotVerified:       "mobile number not verified",
+	ErrProfileNotCompleted:     "profile not completed",
+	ErrReceiversNotFound:       "receivers not found",
+	ErrSendingNumberNotFound:   "sending number not found",
+	ErrSendingNumberExpired:    "sending number has expired",
+}
+
+// Description returns the documented meaning of the code
+func (c ErrorCode) Description() string {
+	if description, ok := errorCodeDescriptions[c]; ok {
+		return description
+	}
+	return "undocumented error code"
+}
+
+// Known reports whether the code is documented
+func (c ErrorCode) Known() bool {
+	_, ok := errorCodeDescriptions[c]
+	return ok
+}
+
+func (c ErrorCode) Error() string {
+	return fmt.Sprintf("mediana error %d: %s", int(c), c.Description())
+}
+
+// parseErrorCode reads a code sent as a string, e.g. meta.code
+func parseErrorCode(s string) (ErrorCode, bool) {
+	code, err := strconv.Atoi(s)
+	if err != nil || code < 1000 {
+		return 0, false
+	}
+	return ErrorCode(code), true
+}
+
+// Is reports whether any error in err's chain matches target. It mirrors the
+// standard library so callers do not need to import both errors packages.
+func Is(err, target error) bool {
+	return stderrors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target. It mirrors the
+// standard library so callers do not need to import both errors packages.
+func As(err error, target interface{}) bool {
+	return stderrors.As(err, target)
+}
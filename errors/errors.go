@@ -2,17 +2,94 @@ package errors
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 )
 
+// ErrCircuitOpen is returned without calling the API while the client's circuit breaker is open
+var ErrCircuitOpen = &CircuitOpenError{}
+
+// ErrNoSendingLine is returned when the account has no line usable for a send
+var ErrNoSendingLine = stderrors.New("no usable sending line")
+
+// Idempotency errors are returned for a keyed send that was not made, so that
+// it is not delivered twice
+var (
+	// ErrIdempotencyKeyReused means the key was already used for a different payload
+	ErrIdempotencyKeyReused = stderrors.New("idempotency key was already used for a different payload")
+	// ErrIdempotencyInFlight means a send with the same key is still running
+	ErrIdempotencyInFlight = stderrors.New("a send with the same idempotency key is in flight")
+	// ErrIdempotencyOutcomeUnknown means an earlier send with the same key ended
+	// without a clear outcome and may have been delivered
+	ErrIdempotencyOutcomeUnknown = stderrors.New("an earlier send with the same idempotency key may have been delivered")
+)
+
+// CircuitOpenError reports a call rejected by an open circuit breaker
+type CircuitOpenError struct {
+	// RetryAt is when the breaker lets a trial request through again
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.RetryAt.IsZero() {
+		return "circuit breaker is open"
+	}
+	return fmt.Sprintf("circuit breaker is open until %s", e.RetryAt.Format(time.RFC3339))
+}
+
+// Is makes every CircuitOpenError match ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	_, ok := target.(*CircuitOpenError)
+	return ok
+}
+
 type APIError struct {
 	StatusCode int
 	Code       string
 	Message    string
 	Errors     []string
 	Details    map[string]interface{}
+	// ErrorCodes holds every Mediana error code reported in the response
+	ErrorCodes []ErrorCode
+	// Fields holds the per-field validation failures from meta.errors
+	Fields []FieldError
+
+	// RawBody is a bounded copy of the response body, kept even when it is not JSON
+	RawBody []byte
+	// Header holds the response headers
+	Header http.Header
+	// Method and Endpoint identify the request that failed
+	Method   string
+	Endpoint string
+	// RequestID is the server or proxy request ID, when one was sent
+	RequestID string
+}
+
+const (
+	// maxErrorBody bounds how much of an error response is read
+	maxErrorBody = 1 << 20
+	// maxRawBody bounds the copy of the body kept on APIError
+	maxRawBody = 4 << 10
+	// maxBodySnippet bounds the body excerpt put in the message of an undecodable response
+	maxBodySnippet = 200
+)
+
+// requestIDHeaders are checked in order for a server request ID
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Correlation-Id", "X-Trace-Id", "Cf-Ray"}
+
+// FieldError is a validation failure Mediana reported for a single request field
+type FieldError struct {
+	Key      string
+	Messages []string
+	Code     ErrorCode
+}
+
+func (f FieldError) Error() string {
+	return fmt.Sprintf("%s: %s (code: %d)", f.Key, strings.Join(f.Messages, ", "), int(f.Code))
 }
 
 func (e *APIError) Error() string {
@@ -22,37 +99,117 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
 }
 
+// FieldErrors returns the validation failures reported for key, compared case-insensitively
+func (e *APIError) FieldErrors(key string) []FieldError {
+	var fields []FieldError
+	for _, f := range e.Fields {
+		if strings.EqualFold(f.Key, key) {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// HasFieldError reports whether a validation failure was reported for key
+func (e *APIError) HasFieldError(key string) bool {
+	return len(e.FieldErrors(key)) > 0
+}
+
+// HasCode reports whether the response carried the given Mediana error code
+func (e *APIError) HasCode(code ErrorCode) bool {
+	for _, c := range e.ErrorCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Is makes errors.Is match an APIError against any ErrorCode it carries
+func (e *APIError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && e.HasCode(code)
+}
+
+// As lets errors.As extract the first ErrorCode carried by the error
+func (e *APIError) As(target interface{}) bool {
+	code, ok := target.(*ErrorCode)
+	if !ok || len(e.ErrorCodes) == 0 {
+		return false
+	}
+	*code = e.ErrorCodes[0]
+	return true
+}
+
 func ParseError(resp *http.Response) error {
 	var errorResponse struct {
 		Meta struct {
 			Code         string `json:"code"`
 			ErrorMessage string `json:"errorMessage"`
 			Errors       []struct {
-				Key      string   `json:"key"`
-				Errors   []string `json:"errors"`
+				Key       string   `json:"key"`
+				Errors    []string `json:"errors"`
 				ErrorCode int      `json:"errorCode"`
 			} `json:"errors"`
 		} `json:"meta"`
-		Data map[string]interface{} `json:"data"`
+		// Data is kept raw: it is only an object on some errors, and its
+		// shape must not cost us the meta code
+		Data json.RawMessage `json:"data"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&errorResponse); err != nil {
-		return &APIError{
-			StatusCode: resp.StatusCode,
-			Message:    "failed to parse error response",
-		}
-	}
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
 
 	apiError := &APIError{
 		StatusCode: resp.StatusCode,
-		Code:       errorResponse.Meta.Code,
-		Message:    errorResponse.Meta.ErrorMessage,
-		Details:    errorResponse.Data,
+		Header:     resp.Header.Clone(),
+		RawBody:    body,
 		Errors:     []string{},
 	}
+	if len(body) > maxRawBody {
+		apiError.RawBody = append([]byte(nil), body[:maxRawBody]...)
+	}
+	if resp.Request != nil {
+		apiError.Method = resp.Request.Method
+		if resp.Request.URL != nil {
+			apiError.Endpoint = resp.Request.URL.Path
+		}
+	}
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			apiError.RequestID = id
+			break
+		}
+	}
+
+	if readErr != nil || json.Unmarshal(body, &errorResponse) != nil {
+		apiError.Message = "failed to parse error response"
+		if snippet := bodySnippet(body); snippet != "" {
+			apiError.Message += ": " + snippet
+		}
+		return apiError
+	}
+
+	apiError.Code = errorResponse.Meta.Code
+	apiError.Message = errorResponse.Meta.ErrorMessage
+	var details map[string]interface{}
+	if json.Unmarshal(errorResponse.Data, &details) == nil {
+		apiError.Details = details
+	}
+
+	if code, ok := parseErrorCode(errorResponse.Meta.Code); ok {
+		apiError.addCode(code)
+	}
 
 	// Extract detailed error messages
 	for _, errDetail := range errorResponse.Meta.Errors {
+		if errDetail.ErrorCode != 0 {
+			apiError.addCode(ErrorCode(errDetail.ErrorCode))
+		}
+		apiError.Fields = append(apiError.Fields, FieldError{
+			Key:      errDetail.Key,
+			Messages: errDetail.Errors,
+			Code:     ErrorCode(errDetail.ErrorCode),
+		})
 		for _, errMsg := range errDetail.Errors {
 			apiError.Errors = append(apiError.Errors, fmt.Sprintf("%s: %s (code: %d)", errDetail.Key, errMsg, errDetail.ErrorCode))
 		}
@@ -60,3 +217,19 @@ func ParseError(resp *http.Response) error {
 
 	return apiError
 }
+
+// addCode records a code once
+func (e *APIError) addCode(code ErrorCode) {
+	if !e.HasCode(code) {
+		e.ErrorCodes = append(e.ErrorCodes, code)
+	}
+}
+
+// bodySnippet returns the start of a body as a single trimmed line
+func bodySnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if runes := []rune(snippet); len(runes) > maxBodySnippet {
+		snippet = string(runes[:maxBodySnippet]) + "..."
+	}
+	return snippet
+}
@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func errorResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"X-Request-Id": []string{"req-1"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{Method: http.MethodPost},
+	}
+}
+
+func TestParseErrorKeepsCodeWhateverTheDataShape(t *testing.T) {
+	for _, data := range []string{`[]`, `"oops"`, `12`, `null`, `{"balance":0}`} {
+		err := ParseError(errorResponse(http.StatusBadRequest, `{"meta":{"code":"1042","errorMessage":"low balance"},"data":`+data+`}`))
+
+		if !Is(err, ErrInsufficientBalance) {
+			t.Errorf("data %s: Is(err, ErrInsufficientBalance) = false, err = %v", data, err)
+		}
+		apiErr := err.(*APIError)
+		if apiErr.Message != "low balance" {
+			t.Errorf("data %s: Message = %q, want %q", data, apiErr.Message, "low balance")
+		}
+		if wantDetails := strings.HasPrefix(data, "{"); (apiErr.Details != nil) != wantDetails {
+			t.Errorf("data %s: Details = %v", data, apiErr.Details)
+		}
+	}
+}
+
+func TestParseErrorFieldErrors(t *testing.T) {
+	err := ParseError(errorResponse(http.StatusBadRequest, `{"meta":{"code":"1041","errors":[{"key":"recipients","errors":["invalid"],"errorCode":1041}]},"data":[]}`))
+
+	apiErr := err.(*APIError)
+	if !apiErr.HasFieldError("recipients") {
+		t.Errorf("HasFieldError(recipients) = false, Fields = %+v", apiErr.Fields)
+	}
+	if !Is(err, ErrInvalidReceiver) {
+		t.Error("Is(err, ErrInvalidReceiver) = false")
+	}
+	if apiErr.RequestID != "req-1" {
+		t.Errorf("RequestID = %q, want req-1", apiErr.RequestID)
+	}
+}
+
+func TestParseErrorWithoutJSONBody(t *testing.T) {
+	err := ParseError(errorResponse(http.StatusBadGateway, "<html>bad gateway</html>"))
+
+	apiErr := err.(*APIError)
+	if !strings.Contains(apiErr.Message, "bad gateway") {
+		t.Errorf("Message = %q, want the body snippet", apiErr.Message)
+	}
+	if string(apiErr.RawBody) != "<html>bad gateway</html>" {
+		t.Errorf("RawBody = %q", apiErr.RawBody)
+	}
+	if !IsRetryable(err) {
+		t.Error("IsRetryable(502) = false")
+	}
+}
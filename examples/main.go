@@ -330,10 +330,18 @@ func testGetSendingLines(c *client.Client) {
 	}
 	respJSON, _ := json.MarshalIndent(resp, "", "  ")
 	fmt.Printf("📥 Response:\n%s\n", string(respJSON))
-	fmt.Println("✅ Sending Line:")
-	fmt.Printf("  - Number: %s, Description: %s, Dedicated: %v, Advertisement: %v, Service: %v, UsableUntil: %s\n",
-		resp.Data.Number, resp.Data.Description, resp.Data.IsDedicated, resp.Data.IsAdvertisement, 
-		resp.Data.IsService, resp.Data.UsableUntil)
+	fmt.Printf("✅ Sending Lines (%d):\n", len(resp.Data))
+	for _, line := range resp.Data {
+		fmt.Printf("  - Number: %s, Description: %s, Dedicated: %v, Advertisement: %v, Service: %v, UsableUntil: %s\n",
+			line.Number, line.Description, line.IsDedicated, line.IsAdvertisement,
+			line.IsService, line.UsableUntil)
+	}
+
+	if line, ok := client.SelectLine(resp.Data, models.MessageTypeService, time.Now()); ok {
+		fmt.Printf("✅ Selected service line: %s\n", line.Number)
+	} else {
+		fmt.Println("⚠️  No usable service line")
+	}
 }
 
 func testGetPatternDetail(c *client.Client, patternCode string) {
@@ -1,3 +1,3 @@
 module github.com/AryanHamedani/mediana-go-sdk // v1.1.2
 
-go 1.20
+go 1.21
@@ -0,0 +1,184 @@
+// Package inbox reads the messages customers send to the account's lines:
+// a Poller streams new messages and a Router dispatches them to handlers.
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+const (
+	defaultInterval   = 10 * time.Second
+	defaultMaxBackoff = 5 * time.Minute
+)
+
+// Fetcher reads inbox messages; *client.Client satisfies it
+type Fetcher interface {
+	GetInbox(ctx context.Context, status string) (*models.InboxResponse, error)
+}
+
+// Poller polls the inbox at an interval and delivers every message once
+type Poller struct {
+	fetcher    Fetcher
+	status     string
+	interval   time.Duration
+	maxBackoff time.Duration
+	seen       SeenStore
+	onError    func(error)
+}
+
+// PollerOption configures a Poller
+type PollerOption func(*Poller)
+
+// NewPoller creates a poller reading new messages every 10 seconds
+func NewPoller(fetcher Fetcher, options ...PollerOption) *Poller {
+	p := &Poller{
+		fetcher:    fetcher,
+		status:     models.InboxStatusNew,
+		interval:   defaultInterval,
+		maxBackoff: defaultMaxBackoff,
+		seen:       NewMemorySeenStore(0),
+	}
+
+	for _, opt := range options {
+		opt(p)
+	}
+
+	return p
+}
+
+// WithInterval sets how often the inbox is polled
+func WithInterval(interval time.Duration) PollerOption {
+	return func(p *Poller) {
+		if interval > 0 {
+			p.interval = interval
+		}
+	}
+}
+
+// WithStatus sets the inbox status that is polled
+func WithStatus(status string) PollerOption {
+	return func(p *Poller) {
+		p.status = status
+	}
+}
+
+// WithMaxBackoff caps the wait between polls after consecutive errors
+func WithMaxBackoff(maxBackoff time.Duration) PollerOption {
+	return func(p *Poller) {
+		if maxBackoff > 0 {
+			p.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithSeenStore sets the store used to skip messages that were already delivered
+func WithSeenStore(store SeenStore) PollerOption {
+	return func(p *Poller) {
+		p.seen = store
+	}
+}
+
+// WithErrorHandler is called with every failed poll
+func WithErrorHandler(onError func(error)) PollerOption {
+	return func(p *Poller) {
+		p.onError = onError
+	}
+}
+
+// Run polls until ctx is done, calling handle once for every new message.
+// It returns ctx's error.
+func (p *Poller) Run(ctx context.Context, handle func(context.Context, models.InboxMessage)) error {
+	failures := 0
+	for {
+		if err := p.poll(ctx, handle); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			failures++
+			if p.onError != nil {
+				p.onError(err)
+			}
+		} else {
+			failures = 0
+		}
+
+		timer := time.NewTimer(p.wait(failures))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Messages starts polling in the background and streams new messages on the
+// returned channel, which is closed once ctx is done
+func (p *Poller) Messages(ctx context.Context) <-chan models.InboxMessage {
+	messages := make(chan models.InboxMessage)
+	go func() {
+		defer close(messages)
+		p.Run(ctx, func(ctx context.Context, msg models.InboxMessage) {
+			select {
+			case messages <- msg:
+			case <-ctx.Done():
+			}
+		})
+	}()
+	return messages
+}
+
+// poll fetches the inbox once and hands over the messages not seen before
+func (p *Poller) poll(ctx context.Context, handle func(context.Context, models.InboxMessage)) error {
+	resp, err := p.fetcher.GetInbox(ctx, p.status)
+	if err != nil {
+		return fmt.Errorf("failed to poll inbox: %w", err)
+	}
+
+	for _, msg := range resp.Data {
+		key := MessageKey(msg)
+		seen, err := p.seen.Seen(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to read seen store: %w", err)
+		}
+		if seen {
+			continue
+		}
+
+		handle(ctx, msg)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err := p.seen.MarkSeen(ctx, key); err != nil {
+			return fmt.Errorf("failed to write seen store: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// wait returns the delay before the next poll, doubling it after every consecutive failure
+func (p *Poller) wait(failures int) time.Duration {
+	wait := p.interval
+	for i := 0; i < failures; i++ {
+		wait *= 2
+		if wait >= p.maxBackoff {
+			return p.maxBackoff
+		}
+	}
+	return wait
+}
+
+// MessageKey identifies a message for deduplication: its Id, or its ReceiveId when Id is empty
+func MessageKey(msg models.InboxMessage) string {
+	if msg.Id != "" {
+		return msg.Id
+	}
+	return "receive:" + strconv.FormatInt(msg.ReceiveId, 10)
+}
@@ -0,0 +1,117 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// fakeFetcher serves a fixed sequence of inbox pages, repeating the last one
+type fakeFetcher struct {
+	mu    sync.Mutex
+	pages []fakePage
+	calls int
+}
+
+type fakePage struct {
+	messages []models.InboxMessage
+	err      error
+}
+
+func (f *fakeFetcher) GetInbox(ctx context.Context, status string) (*models.InboxResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	page := f.pages[len(f.pages)-1]
+	if f.calls < len(f.pages) {
+		page = f.pages[f.calls]
+	}
+	f.calls++
+	if page.err != nil {
+		return nil, page.err
+	}
+	return &models.InboxResponse{Data: page.messages}, nil
+}
+
+func message(id string) models.InboxMessage {
+	return models.InboxMessage{Id: id, MessageText: "text " + id}
+}
+
+func TestPollerDeliversEveryMessageOnce(t *testing.T) {
+	fetcher := &fakeFetcher{pages: []fakePage{
+		{messages: []models.InboxMessage{message("1"), message("2")}},
+		{err: errors.New("temporary")},
+		{messages: []models.InboxMessage{message("2"), message("3")}},
+		{messages: []models.InboxMessage{message("1"), message("3")}},
+	}}
+
+	var errs int
+	poller := NewPoller(fetcher, WithInterval(time.Millisecond), WithMaxBackoff(2*time.Millisecond),
+		WithErrorHandler(func(error) { errs++ }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var got []string
+	for msg := range poller.Messages(ctx) {
+		got = append(got, msg.Id)
+		if len(got) == 3 {
+			// Give the poller a few more rounds to deliver a duplicate
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}
+	}
+
+	if want := []string{"1", "2", "3"}; len(got) != len(want) || got[0] != "1" || got[1] != "2" || got[2] != "3" {
+		t.Errorf("messages = %v, want %v", got, want)
+	}
+	if errs != 1 {
+		t.Errorf("errors = %d, want 1", errs)
+	}
+}
+
+func TestPollerSharesSeenStore(t *testing.T) {
+	seen := NewMemorySeenStore(0)
+	seen.MarkSeen(context.Background(), "1")
+
+	fetcher := &fakeFetcher{pages: []fakePage{{messages: []models.InboxMessage{message("1"), message("2")}}}}
+	poller := NewPoller(fetcher, WithInterval(time.Hour), WithSeenStore(seen))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var got []string
+	poller.Run(ctx, func(ctx context.Context, msg models.InboxMessage) {
+		got = append(got, msg.Id)
+		cancel()
+	})
+
+	if len(got) != 1 || got[0] != "2" {
+		t.Errorf("messages = %v, want [2]", got)
+	}
+}
+
+func TestMemorySeenStoreEvictsOldest(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemorySeenStore(2)
+	for _, key := range []string{"a", "b", "c"} {
+		store.MarkSeen(ctx, key)
+	}
+
+	for key, want := range map[string]bool{"a": false, "b": true, "c": true} {
+		if got, _ := store.Seen(ctx, key); got != want {
+			t.Errorf("Seen(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestMessageKey(t *testing.T) {
+	if got := MessageKey(models.InboxMessage{Id: "abc", ReceiveId: 7}); got != "abc" {
+		t.Errorf("MessageKey() = %q, want abc", got)
+	}
+	if got := MessageKey(models.InboxMessage{ReceiveId: 7}); got != "receive:7" {
+		t.Errorf("MessageKey() = %q, want receive:7", got)
+	}
+}
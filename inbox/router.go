@@ -0,0 +1,139 @@
+package inbox
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/AryanHamedani/mediana-go-sdk/internal/persian"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// Sender sends replies; *client.Client satisfies it
+type Sender interface {
+	SendSMS(ctx context.Context, req models.SMSRequest) (*models.SMSResponse, error)
+}
+
+// Request is an inbound message being routed to a handler
+type Request struct {
+	Message models.InboxMessage
+	// Text is the message text after Normalize
+	Text string
+	// Matches holds the submatches of a regexp route, nil for other routes
+	Matches []string
+
+	sender Sender
+}
+
+// Reply sends text to the sender of the message from the line it arrived on
+func (r *Request) Reply(ctx context.Context, text string) (*models.SMSResponse, error) {
+	if r.sender == nil {
+		return nil, fmt.Errorf("router has no sender to reply with")
+	}
+	return r.sender.SendSMS(ctx, models.SMSRequest{
+		SendingNumber: r.Message.DestinationAddress,
+		Recipients:    []string{r.Message.SourceAddress},
+		MessageText:   text,
+	})
+}
+
+// HandlerFunc handles a routed message
+type HandlerFunc func(ctx context.Context, req *Request) error
+
+type route struct {
+	keyword string
+	pattern *regexp.Regexp
+	handler HandlerFunc
+}
+
+// Router dispatches inbound messages to handlers registered by keyword or
+// regular expression. Routes are tried in registration order and the first
+// match wins; unmatched messages go to the fallback handler.
+type Router struct {
+	sender   Sender
+	routes   []route
+	fallback HandlerFunc
+	onError  func(models.InboxMessage, error)
+}
+
+// RouterOption configures a Router
+type RouterOption func(*Router)
+
+// WithRouteErrorHandler is called when a handler fails in HandleMessage
+func WithRouteErrorHandler(onError func(models.InboxMessage, error)) RouterOption {
+	return func(r *Router) {
+		r.onError = onError
+	}
+}
+
+// NewRouter creates a router that replies through sender, which may be nil
+// when no handler replies
+func NewRouter(sender Sender, options ...RouterOption) *Router {
+	r := &Router{sender: sender}
+
+	for _, opt := range options {
+		opt(r)
+	}
+
+	return r
+}
+
+// Normalize prepares message text for matching: Persian and Arabic digits
+// become ASCII, Arabic letter forms become Persian and whitespace is collapsed
+func Normalize(text string) string {
+	return persian.Normalize(text)
+}
+
+// HandleKeyword routes messages whose first words are keyword, ignoring case,
+// e.g. "STOP" matches "stop" and "Stop please"
+func (r *Router) HandleKeyword(keyword string, handler HandlerFunc) {
+	r.routes = append(r.routes, route{keyword: strings.ToLower(Normalize(keyword)), handler: handler})
+}
+
+// HandleRegexp routes messages whose normalized text matches pattern; the
+// submatches are passed in Request.Matches
+func (r *Router) HandleRegexp(pattern *regexp.Regexp, handler HandlerFunc) {
+	r.routes = append(r.routes, route{pattern: pattern, handler: handler})
+}
+
+// HandleFallback handles messages no route matched
+func (r *Router) HandleFallback(handler HandlerFunc) {
+	r.fallback = handler
+}
+
+// Route dispatches msg to the first matching handler and returns its error.
+// A message without a matching route or fallback is ignored.
+func (r *Router) Route(ctx context.Context, msg models.InboxMessage) error {
+	req := &Request{Message: msg, Text: Normalize(msg.MessageText), sender: r.sender}
+	lower := strings.ToLower(req.Text)
+
+	for _, rt := range r.routes {
+		if rt.pattern != nil {
+			if matches := rt.pattern.FindStringSubmatch(req.Text); matches != nil {
+				req.Matches = matches
+				return rt.handler(ctx, req)
+			}
+			continue
+		}
+
+		if lower == rt.keyword || strings.HasPrefix(lower, rt.keyword+" ") {
+			return rt.handler(ctx, req)
+		}
+	}
+
+	if r.fallback != nil {
+		return r.fallback(ctx, req)
+	}
+	return nil
+}
+
+// HandleMessage routes msg and reports a failure to the error handler. It
+// plugs the router into a Poller:
+//
+//	poller.Run(ctx, router.HandleMessage)
+func (r *Router) HandleMessage(ctx context.Context, msg models.InboxMessage) {
+	if err := r.Route(ctx, msg); err != nil && r.onError != nil {
+		r.onError(msg, err)
+	}
+}
@@ -0,0 +1,129 @@
+package inbox
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// fakeSender records the replies sent through it
+type fakeSender struct {
+	sent []models.SMSRequest
+}
+
+func (f *fakeSender) SendSMS(ctx context.Context, req models.SMSRequest) (*models.SMSResponse, error) {
+	f.sent = append(f.sent, req)
+	return &models.SMSResponse{}, nil
+}
+
+// testRouter registers the routes of the tests below; handled records the
+// route name and submatches of the last routed message
+func testRouter(handled *[]string) *Router {
+	record := func(name string) HandlerFunc {
+		return func(ctx context.Context, req *Request) error {
+			*handled = append([]string{name}, req.Matches...)
+			return nil
+		}
+	}
+
+	router := NewRouter(nil)
+	router.HandleKeyword("لغو", record("cancel"))
+	router.HandleKeyword("STOP", record("stop"))
+	router.HandleRegexp(regexp.MustCompile(`^CONFIRM (\d+)$`), record("confirm"))
+	router.HandleRegexp(regexp.MustCompile(`^CONFIRM`), record("confirm-any"))
+	router.HandleKeyword("confirm", record("confirm-keyword"))
+	router.HandleFallback(record("fallback"))
+	return router
+}
+
+func TestRouterRoutesNormalizedText(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"لغو", []string{"cancel"}},
+		{"لـغو‏", []string{"cancel"}},           // tatweel and a direction mark
+		{"  لغو   اشتراک ", []string{"cancel"}}, // keyword prefix, extra spaces
+		{"لغوش", []string{"fallback"}},          // a longer word is not the keyword
+		{"stop", []string{"stop"}},
+		{"Stop please", []string{"stop"}},
+		{"CONFIRM ۱۲۳۴", []string{"confirm", "CONFIRM 1234", "1234"}},
+		{"CONFIRM ١٢٣", []string{"confirm", "CONFIRM 123", "123"}},
+		// Routes are tried in registration order: the second regexp wins over the keyword
+		{"CONFIRM now", []string{"confirm-any", "CONFIRM"}},
+		{"confirm now", []string{"confirm-keyword"}},
+		{"hello", []string{"fallback"}},
+	}
+	for _, tt := range tests {
+		var handled []string
+		if err := testRouter(&handled).Route(context.Background(), models.InboxMessage{MessageText: tt.text}); err != nil {
+			t.Fatalf("Route(%q) error = %v", tt.text, err)
+		}
+		if len(handled) != len(tt.want) {
+			t.Errorf("Route(%q) handled = %q, want %q", tt.text, handled, tt.want)
+			continue
+		}
+		for i := range tt.want {
+			if handled[i] != tt.want[i] {
+				t.Errorf("Route(%q) handled = %q, want %q", tt.text, handled, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"كيك":      "کیک",
+		"۰۹۱۲ ٣٤٥": "0912 345",
+		"مي‌خواهم": "می خواهم",
+		"سَلام":    "سلام",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRouterWithoutFallbackIgnoresMessage(t *testing.T) {
+	router := NewRouter(nil)
+	router.HandleKeyword("stop", func(ctx context.Context, req *Request) error {
+		t.Error("handler called for an unmatched message")
+		return nil
+	})
+	if err := router.Route(context.Background(), models.InboxMessage{MessageText: "hello"}); err != nil {
+		t.Errorf("Route() error = %v", err)
+	}
+}
+
+func TestRequestReplySwapsAddresses(t *testing.T) {
+	sender := &fakeSender{}
+	router := NewRouter(sender)
+	router.HandleKeyword("stop", func(ctx context.Context, req *Request) error {
+		_, err := req.Reply(ctx, "unsubscribed")
+		return err
+	})
+
+	msg := models.InboxMessage{MessageText: "STOP", SourceAddress: "09121234567", DestinationAddress: "3000123"}
+	if err := router.Route(context.Background(), msg); err != nil {
+		t.Fatalf("Route() error = %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("replies = %d, want 1", len(sender.sent))
+	}
+	reply := sender.sent[0]
+	if reply.SendingNumber != "3000123" || len(reply.Recipients) != 1 || reply.Recipients[0] != "09121234567" || reply.MessageText != "unsubscribed" {
+		t.Errorf("reply = %+v, want from 3000123 to 09121234567", reply)
+	}
+}
+
+func TestRequestReplyWithoutSender(t *testing.T) {
+	req := &Request{Message: models.InboxMessage{SourceAddress: "09121234567"}}
+	if _, err := req.Reply(context.Background(), "hi"); err == nil {
+		t.Error("Reply() error = nil, want an error without a sender")
+	}
+}
@@ -0,0 +1,56 @@
+package inbox
+
+import (
+	"context"
+	"sync"
+)
+
+const defaultSeenCapacity = 10000
+
+// SeenStore remembers which messages were already delivered
+type SeenStore interface {
+	Seen(ctx context.Context, key string) (bool, error)
+	MarkSeen(ctx context.Context, key string) error
+}
+
+// MemorySeenStore is an in-memory SeenStore that forgets the oldest keys once
+// it holds capacity keys
+type MemorySeenStore struct {
+	mu       sync.Mutex
+	capacity int
+	keys     map[string]struct{}
+	order    []string
+}
+
+// NewMemorySeenStore creates a store holding up to capacity keys; zero means 10000
+func NewMemorySeenStore(capacity int) *MemorySeenStore {
+	if capacity <= 0 {
+		capacity = defaultSeenCapacity
+	}
+	return &MemorySeenStore{capacity: capacity, keys: make(map[string]struct{})}
+}
+
+func (s *MemorySeenStore) Seen(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.keys[key]
+	return ok, nil
+}
+
+func (s *MemorySeenStore) MarkSeen(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.keys[key]; ok {
+		return nil
+	}
+
+	if len(s.order) >= s.capacity {
+		delete(s.keys, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.keys[key] = struct{}{}
+	s.order = append(s.order, key)
+	return nil
+}
@@ -0,0 +1,47 @@
+// Package persian normalizes Persian and Arabic text typed on phones.
+package persian
+
+import "strings"
+
+var digitReplacer = strings.NewReplacer(
+	"۰", "0", "۱", "1", "۲", "2", "۳", "3", "۴", "4",
+	"۵", "5", "۶", "6", "۷", "7", "۸", "8", "۹", "9",
+	"٠", "0", "١", "1", "٢", "2", "٣", "3", "٤", "4",
+	"٥", "5", "٦", "6", "٧", "7", "٨", "8", "٩", "9",
+)
+
+// NormalizeDigits replaces Persian and Arabic-Indic digits with ASCII digits
+func NormalizeDigits(s string) string {
+	return digitReplacer.Replace(s)
+}
+
+var letterReplacer = strings.NewReplacer(
+	"ي", "ی", // Arabic yeh
+	"ى", "ی", // Arabic alef maksura
+	"ك", "ک", // Arabic kaf
+	"ة", "ه", // teh marbuta
+	"ۀ", "ه", // heh with yeh above
+	"أ", "ا", "إ", "ا", "ٱ", "ا", // alef variants
+	"ؤ", "و",
+	"\u0640", "", // tatweel
+	"\u200c", " ", // zero-width non-joiner
+	"\u200f", "", "\u200e", "", // direction marks
+)
+
+// NormalizeLetters maps Arabic letter forms to their Persian equivalents and
+// drops tatweel, diacritics and direction marks
+func NormalizeLetters(s string) string {
+	s = letterReplacer.Replace(s)
+	return strings.Map(func(r rune) rune {
+		// Arabic diacritics (harakat, tanwin, superscript alef)
+		if (r >= '\u064b' && r <= '\u065f') || r == '\u0670' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Normalize applies NormalizeDigits and NormalizeLetters and collapses whitespace
+func Normalize(s string) string {
+	return strings.Join(strings.Fields(NormalizeLetters(NormalizeDigits(s))), " ")
+}
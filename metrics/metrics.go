@@ -0,0 +1,90 @@
+// Package metrics records per-operation measurements of the Mediana client.
+// It is independent of any metrics backend; see the prometheus subpackage for
+// a Prometheus adapter.
+package metrics
+
+import (
+	"context"
+	stderrors "errors"
+	"strconv"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/client"
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+)
+
+// Failure codes reported for errors that carry no Mediana code
+const (
+	FailureTransport   = "transport"
+	FailureCircuitOpen = "circuit_open"
+)
+
+// Recorder receives measurements for every API call
+type Recorder interface {
+	// IncRequests counts a call
+	IncRequests(operation string)
+	// IncFailures counts a failed call by its Mediana error code
+	IncFailures(operation, code string)
+	// ObserveLatency records the duration of a call
+	ObserveLatency(operation string, duration time.Duration)
+	// AddRecipients counts the recipients of a send
+	AddRecipients(operation string, count int)
+	// AddSmsItems counts the SMS items returned with the given status
+	AddSmsItems(operation, status string, count int)
+}
+
+// Middleware returns a client.Middleware that reports every call to recorder
+//
+//	c := client.New(apiKey, client.WithMiddleware(metrics.Middleware(recorder)))
+func Middleware(recorder Recorder) client.Middleware {
+	return func(next client.Handler) client.Handler {
+		return func(ctx context.Context, call *client.Call) error {
+			start := time.Now()
+			err := next(ctx, call)
+
+			recorder.IncRequests(call.Operation)
+			recorder.ObserveLatency(call.Operation, time.Since(start))
+
+			if err != nil {
+				recorder.IncFailures(call.Operation, FailureCode(err))
+				return err
+			}
+
+			if count, ok := call.RecipientCount(); ok {
+				recorder.AddRecipients(call.Operation, count)
+			}
+
+			counts := make(map[string]int)
+			for _, item := range call.SmsItems() {
+				status := string(item.Status.Normalize())
+				if status == "" {
+					status = "unknown"
+				}
+				counts[status]++
+			}
+			for status, count := range counts {
+				recorder.AddSmsItems(call.Operation, status, count)
+			}
+			return nil
+		}
+	}
+}
+
+// FailureCode returns the label a failed call is counted under: the Mediana
+// meta code of an API error, its HTTP status when the code is missing, or one
+// of the Failure constants
+func FailureCode(err error) string {
+	if stderrors.Is(err, errors.ErrCircuitOpen) {
+		return FailureCircuitOpen
+	}
+
+	var apiErr *errors.APIError
+	if stderrors.As(err, &apiErr) {
+		if apiErr.Code != "" {
+			return apiErr.Code
+		}
+		return strconv.Itoa(apiErr.StatusCode)
+	}
+
+	return FailureTransport
+}
@@ -0,0 +1,170 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/client"
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// fakeRecorder keeps every measurement as a "name operation [label] value" line
+type fakeRecorder struct {
+	mu      sync.Mutex
+	lines   []string
+	latency []time.Duration
+}
+
+func (r *fakeRecorder) add(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, fmt.Sprintf(format, args...))
+}
+
+func (r *fakeRecorder) IncRequests(operation string) { r.add("requests %s", operation) }
+
+func (r *fakeRecorder) IncFailures(operation, code string) { r.add("failures %s %s", operation, code) }
+
+func (r *fakeRecorder) ObserveLatency(operation string, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latency = append(r.latency, duration)
+}
+
+func (r *fakeRecorder) AddRecipients(operation string, count int) {
+	r.add("recipients %s %d", operation, count)
+}
+
+func (r *fakeRecorder) AddSmsItems(operation, status string, count int) {
+	r.add("smsItems %s %s %d", operation, status, count)
+}
+
+func (r *fakeRecorder) measurements() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	lines := append([]string(nil), r.lines...)
+	sort.Strings(lines)
+	return lines
+}
+
+func newRecordedClient(t *testing.T, status int, body string, options ...client.Option) (*client.Client, *fakeRecorder) {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	recorder := &fakeRecorder{}
+	options = append([]client.Option{client.WithBaseURL(srv.URL), client.WithMiddleware(Middleware(recorder))}, options...)
+	return client.New("key", options...), recorder
+}
+
+func TestMiddlewareRecordsSuccessfulSend(t *testing.T) {
+	c, recorder := newRecordedClient(t, http.StatusOK, `{"data":{"requestCode":"rc-1","smsItems":[
+		{"smsItemId":"1","status":"delivered"},
+		{"smsItemId":"2","status":"Delivered"},
+		{"smsItemId":"3","status":"Failed"},
+		{"smsItemId":"4"}]}}`)
+
+	req := models.SMSRequest{MessageText: "hi", Recipients: []string{"09121111111", "09122222222", "09123333333", "09124444444"}}
+	if _, err := c.SendSMS(context.Background(), req); err != nil {
+		t.Fatalf("SendSMS() error = %v", err)
+	}
+
+	want := []string{
+		"recipients sendSms 4",
+		"requests sendSms",
+		"smsItems sendSms Delivered 2",
+		"smsItems sendSms Failed 1",
+		"smsItems sendSms unknown 1",
+	}
+	if got := recorder.measurements(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("measurements = %q, want %q", got, want)
+	}
+	if len(recorder.latency) != 1 {
+		t.Errorf("latency observations = %d, want 1", len(recorder.latency))
+	}
+}
+
+func TestMiddlewareLabelsFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   string
+	}{
+		{"meta code", http.StatusBadRequest, `{"meta":{"code":"1041","errorMessage":"invalid"}}`, "failures sendSms 1041"},
+		{"status without code", http.StatusBadRequest, `not json`, "failures sendSms 400"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, recorder := newRecordedClient(t, tt.status, tt.body)
+			if _, err := c.SendSMS(context.Background(), models.SMSRequest{MessageText: "hi", Recipients: []string{"09121111111"}}); err == nil {
+				t.Fatal("SendSMS() error = nil, want the API error")
+			}
+			want := []string{tt.want, "requests sendSms"}
+			if got := recorder.measurements(); fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("measurements = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestMiddlewareLabelsCircuitOpen(t *testing.T) {
+	c, recorder := newRecordedClient(t, http.StatusBadGateway, ``, client.WithCircuitBreaker(client.CircuitBreakerSettings{FailureThreshold: 1}))
+	c.GetAccountBalance(context.Background())
+	if _, err := c.GetAccountBalance(context.Background()); !errors.Is(err, errors.ErrCircuitOpen) {
+		t.Fatalf("GetAccountBalance() error = %v, want ErrCircuitOpen", err)
+	}
+
+	want := []string{"failures getBalance 502", "failures getBalance circuit_open", "requests getBalance", "requests getBalance"}
+	if got := recorder.measurements(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("measurements = %q, want %q", got, want)
+	}
+}
+
+func TestMiddlewareLabelsTransportFailures(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close() // nothing listens, so the call fails before any response
+
+	recorder := &fakeRecorder{}
+	c := client.New("key", client.WithBaseURL(srv.URL), client.WithMiddleware(Middleware(recorder)))
+	if _, err := c.GetAccountBalance(context.Background()); err == nil {
+		t.Fatal("GetAccountBalance() error = nil, want a transport error")
+	}
+
+	want := []string{"failures getBalance transport", "requests getBalance"}
+	if got := recorder.measurements(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("measurements = %q, want %q", got, want)
+	}
+}
+
+func TestFailureCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"meta code", &errors.APIError{StatusCode: 400, Code: "1042"}, "1042"},
+		{"status code", &errors.APIError{StatusCode: 503}, "503"},
+		{"wrapped", fmt.Errorf("send failed: %w", &errors.APIError{StatusCode: 400, Code: "1047"}), "1047"},
+		{"circuit open", &errors.CircuitOpenError{RetryAt: time.Now()}, FailureCircuitOpen},
+		{"transport", fmt.Errorf("failed to send request: %w", context.DeadlineExceeded), FailureTransport},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FailureCode(tt.err); got != tt.want {
+				t.Errorf("FailureCode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package prometheus exposes Mediana client metrics as a prometheus.Collector.
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/AryanHamedani/mediana-go-sdk/metrics"
+)
+
+// Collector is a metrics.Recorder that is also a prometheus.Collector
+//
+//	collector := prometheus.NewCollector("mediana")
+//	registry.MustRegister(collector)
+//	c := client.New(apiKey, client.WithMiddleware(metrics.Middleware(collector)))
+type Collector struct {
+	requests   *prom.CounterVec
+	failures   *prom.CounterVec
+	latency    *prom.HistogramVec
+	recipients *prom.CounterVec
+	smsItems   *prom.CounterVec
+}
+
+var _ metrics.Recorder = (*Collector)(nil)
+
+// NewCollector creates a collector whose metric names start with namespace
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Number of Mediana API calls by operation.",
+		}, []string{"operation"}),
+		failures: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "failures_total",
+			Help:      "Number of failed Mediana API calls by operation and error code.",
+		}, []string{"operation", "code"}),
+		latency: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of Mediana API calls by operation.",
+			Buckets:   prom.DefBuckets,
+		}, []string{"operation"}),
+		recipients: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "recipients_total",
+			Help:      "Number of recipients sent to by operation.",
+		}, []string{"operation"}),
+		smsItems: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "sms_items_total",
+			Help:      "Number of SMS items returned by operation and status.",
+		}, []string{"operation", "status"}),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+	c.requests.Describe(ch)
+	c.failures.Describe(ch)
+	c.latency.Describe(ch)
+	c.recipients.Describe(ch)
+	c.smsItems.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+	c.requests.Collect(ch)
+	c.failures.Collect(ch)
+	c.latency.Collect(ch)
+	c.recipients.Collect(ch)
+	c.smsItems.Collect(ch)
+}
+
+func (c *Collector) IncRequests(operation string) {
+	c.requests.WithLabelValues(operation).Inc()
+}
+
+func (c *Collector) IncFailures(operation, code string) {
+	c.failures.WithLabelValues(operation, code).Inc()
+}
+
+func (c *Collector) ObserveLatency(operation string, duration time.Duration) {
+	c.latency.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+func (c *Collector) AddRecipients(operation string, count int) {
+	c.recipients.WithLabelValues(operation).Add(float64(count))
+}
+
+func (c *Collector) AddSmsItems(operation, status string, count int) {
+	c.smsItems.WithLabelValues(operation, status).Add(float64(count))
+}
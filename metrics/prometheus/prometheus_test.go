@@ -0,0 +1,49 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollectorExposesMeasurements(t *testing.T) {
+	collector := NewCollector("mediana")
+	registry := prom.NewPedanticRegistry()
+	if err := registry.Register(collector); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	collector.IncRequests("sendSms")
+	collector.IncRequests("sendSms")
+	collector.IncFailures("sendSms", "1041")
+	collector.ObserveLatency("sendSms", 250*time.Millisecond)
+	collector.AddRecipients("sendSms", 3)
+	collector.AddSmsItems("sendSms", "Delivered", 2)
+
+	want := `
+# HELP mediana_failures_total Number of failed Mediana API calls by operation and error code.
+# TYPE mediana_failures_total counter
+mediana_failures_total{code="1041",operation="sendSms"} 1
+# HELP mediana_recipients_total Number of recipients sent to by operation.
+# TYPE mediana_recipients_total counter
+mediana_recipients_total{operation="sendSms"} 3
+# HELP mediana_requests_total Number of Mediana API calls by operation.
+# TYPE mediana_requests_total counter
+mediana_requests_total{operation="sendSms"} 2
+# HELP mediana_sms_items_total Number of SMS items returned by operation and status.
+# TYPE mediana_sms_items_total counter
+mediana_sms_items_total{operation="sendSms",status="Delivered"} 2
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(want),
+		"mediana_failures_total", "mediana_recipients_total", "mediana_requests_total", "mediana_sms_items_total")
+	if err != nil {
+		t.Error(err)
+	}
+
+	if got := testutil.CollectAndCount(collector, "mediana_request_duration_seconds"); got != 1 {
+		t.Errorf("latency series = %d, want 1", got)
+	}
+}
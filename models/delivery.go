@@ -0,0 +1,145 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DeliveryState is the delivery status of an SMS item or request. Values the
+// SDK does not know are kept as they are.
+type DeliveryState string
+
+const (
+	StatePending     DeliveryState = "Pending"
+	StateQueued      DeliveryState = "Queued"
+	StateSent        DeliveryState = "Sent"
+	StateDelivered   DeliveryState = "Delivered"
+	StateFailed      DeliveryState = "Failed"
+	StateUndelivered DeliveryState = "Undelivered"
+	StateBlacklisted DeliveryState = "Blacklisted"
+	StateExpired     DeliveryState = "Expired"
+	StateRejected    DeliveryState = "Rejected"
+)
+
+// deliveryStages orders the known states; a state may only move to a later stage
+var deliveryStages = map[DeliveryState]int{
+	StatePending:     0,
+	StateQueued:      1,
+	StateSent:        2,
+	StateDelivered:   3,
+	StateFailed:      3,
+	StateUndelivered: 3,
+	StateBlacklisted: 3,
+	StateExpired:     3,
+	StateRejected:    3,
+}
+
+const terminalStage = 3
+
+// Normalize returns the known state matching s regardless of case, or s unchanged
+func (s DeliveryState) Normalize() DeliveryState {
+	for state := range deliveryStages {
+		if strings.EqualFold(string(s), string(state)) {
+			return state
+		}
+	}
+	return s
+}
+
+// IsKnown reports whether s is one of the states defined above
+func (s DeliveryState) IsKnown() bool {
+	_, ok := deliveryStages[s.Normalize()]
+	return ok
+}
+
+// IsTerminal reports whether s no longer changes
+func (s DeliveryState) IsTerminal() bool {
+	stage, ok := deliveryStages[s.Normalize()]
+	return ok && stage == terminalStage
+}
+
+// TerminalStates returns the known states that no longer change, in name order
+func TerminalStates() []DeliveryState {
+	var states []DeliveryState
+	for state, stage := range deliveryStages {
+		if stage == terminalStage {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+	return states
+}
+
+// IsSuccess reports whether s means the message reached the handset
+func (s DeliveryState) IsSuccess() bool {
+	return s.Normalize() == StateDelivered
+}
+
+// TransitionError reports an illegal move between two delivery states
+type TransitionError struct {
+	SmsItemId string
+	From      DeliveryState
+	To        DeliveryState
+}
+
+func (e *TransitionError) Error() string {
+	if e.SmsItemId != "" {
+		return fmt.Sprintf("sms item %s: illegal delivery state transition %s -> %s", e.SmsItemId, e.From, e.To)
+	}
+	return fmt.Sprintf("illegal delivery state transition %s -> %s", e.From, e.To)
+}
+
+// ValidateTransition returns a *TransitionError when moving from one state to
+// another is not legal: a terminal state that changes, or a state that moves
+// back to an earlier stage (e.g. Sent to Pending). Transitions involving
+// unknown states are accepted because they cannot be judged.
+func ValidateTransition(from, to DeliveryState) error {
+	from, to = from.Normalize(), to.Normalize()
+	if from == to || from == "" {
+		return nil
+	}
+
+	fromStage, fromKnown := deliveryStages[from]
+	toStage, toKnown := deliveryStages[to]
+	if !fromKnown || !toKnown {
+		return nil
+	}
+
+	if fromStage == terminalStage || toStage < fromStage {
+		return &TransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+// ValidateItemTransitions compares two snapshots of the same request and
+// returns an error for every item whose state moved illegally. Items are
+// matched by SmsItemId, or by Recipient when the ID is empty.
+func ValidateItemTransitions(previous, current []SmsItemInfo) []*TransitionError {
+	before := make(map[string]DeliveryState, len(previous))
+	for _, item := range previous {
+		before[item.key()] = item.Status
+	}
+
+	var errs []*TransitionError
+	for _, item := range current {
+		from, ok := before[item.key()]
+		if !ok {
+			continue
+		}
+		if err := ValidateTransition(from, item.Status); err != nil {
+			transitionErr := err.(*TransitionError)
+			transitionErr.SmsItemId = item.SmsItemId
+			errs = append(errs, transitionErr)
+		}
+	}
+	return errs
+}
+
+// key identifies an item across snapshots
+func (i SmsItemInfo) key() string {
+	if i.SmsItemId != "" {
+		return i.SmsItemId
+	}
+	return "recipient:" + i.Recipient
+}
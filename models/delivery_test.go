@@ -0,0 +1,97 @@
+package models
+
+import "testing"
+
+func TestDeliveryStateNormalize(t *testing.T) {
+	tests := []struct {
+		in       DeliveryState
+		want     DeliveryState
+		known    bool
+		terminal bool
+	}{
+		{"delivered", StateDelivered, true, true},
+		{"PENDING", StatePending, true, false},
+		{"Sent", StateSent, true, false},
+		{"Blacklisted", StateBlacklisted, true, true},
+		{"InOperatorQueue", "InOperatorQueue", false, false}, // unknown states are kept as they are
+		{"", "", false, false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Normalize(); got != tt.want {
+			t.Errorf("%q.Normalize() = %q, want %q", tt.in, got, tt.want)
+		}
+		if got := tt.in.IsKnown(); got != tt.known {
+			t.Errorf("%q.IsKnown() = %v, want %v", tt.in, got, tt.known)
+		}
+		if got := tt.in.IsTerminal(); got != tt.terminal {
+			t.Errorf("%q.IsTerminal() = %v, want %v", tt.in, got, tt.terminal)
+		}
+	}
+}
+
+func TestTerminalStates(t *testing.T) {
+	states := TerminalStates()
+	want := []DeliveryState{StateBlacklisted, StateDelivered, StateExpired, StateFailed, StateRejected, StateUndelivered}
+	if len(states) != len(want) {
+		t.Fatalf("TerminalStates() = %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("TerminalStates() = %v, want %v", states, want)
+		}
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	tests := []struct {
+		from, to DeliveryState
+		legal    bool
+	}{
+		{StatePending, StateSent, true},
+		{StatePending, StateDelivered, true},
+		{"queued", "Delivered", true},
+		{StateSent, StateSent, true},
+		{"", StateDelivered, true},
+		{StateSent, StatePending, false},
+		{StateDelivered, StatePending, false},
+		{StateDelivered, StateFailed, false},
+		{"delivered", "PENDING", false},
+		{StateDelivered, "InOperatorQueue", true}, // unknown states cannot be judged
+		{"InOperatorQueue", StatePending, true},
+	}
+	for _, tt := range tests {
+		err := ValidateTransition(tt.from, tt.to)
+		if (err == nil) != tt.legal {
+			t.Errorf("ValidateTransition(%q, %q) = %v, want legal = %v", tt.from, tt.to, err, tt.legal)
+		}
+	}
+}
+
+func TestValidateItemTransitions(t *testing.T) {
+	previous := []SmsItemInfo{
+		{SmsItemId: "1", Recipient: "09121111111", Status: StateDelivered},
+		{SmsItemId: "2", Recipient: "09122222222", Status: StateSent},
+		{Recipient: "09123333333", Status: StateSent},
+		{SmsItemId: "4", Recipient: "09124444444", Status: StateQueued},
+	}
+	current := []SmsItemInfo{
+		{SmsItemId: "1", Recipient: "09121111111", Status: StatePending}, // delivered, then pending again
+		{SmsItemId: "2", Recipient: "09122222222", Status: StateDelivered},
+		{Recipient: "09123333333", Status: StateQueued},                  // matched by recipient
+		{SmsItemId: "5", Recipient: "09124444444", Status: StatePending}, // another item: not compared
+	}
+
+	errs := ValidateItemTransitions(previous, current)
+	if len(errs) != 2 {
+		t.Fatalf("ValidateItemTransitions() = %v, want 2 errors", errs)
+	}
+	if errs[0].SmsItemId != "1" || errs[0].From != StateDelivered || errs[0].To != StatePending {
+		t.Errorf("first error = %+v, want item 1 Delivered -> Pending", errs[0])
+	}
+	if errs[1].SmsItemId != "" || errs[1].From != StateSent || errs[1].To != StateQueued {
+		t.Errorf("second error = %+v, want the recipient-matched item Sent -> Queued", errs[1])
+	}
+	if got := errs[0].Error(); got != "sms item 1: illegal delivery state transition Delivered -> Pending" {
+		t.Errorf("Error() = %q", got)
+	}
+}
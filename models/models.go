@@ -1,5 +1,11 @@
 package models
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
 // Meta represents common metadata in responses
 type Meta struct {
 	Code         string   `json:"code"`
@@ -9,9 +15,9 @@ type Meta struct {
 
 // SmsItemInfo represents information about a single SMS item in responses
 type SmsItemInfo struct {
-	SmsItemId string `json:"smsItemId"`
-	Recipient string `json:"recipient"`
-	Status    string `json:"status,omitempty"`
+	SmsItemId string        `json:"smsItemId"`
+	Recipient string        `json:"recipient"`
+	Status    DeliveryState `json:"status,omitempty"`
 }
 
 // SMSRequest represents a request to send a regular SMS
@@ -26,10 +32,10 @@ type SMSRequest struct {
 type SMSResponse struct {
 	Meta Meta `json:"meta"`
 	Data struct {
-		Succeed     bool         `json:"succeed"`
-		RequestCode string       `json:"requestCode"`
-		Message     string       `json:"message"`
-		Status      string       `json:"status"`
+		Succeed     bool          `json:"succeed"`
+		RequestCode string        `json:"requestCode"`
+		Message     string        `json:"message"`
+		Status      string        `json:"status"`
 		SmsItems    []SmsItemInfo `json:"smsItems"`
 	} `json:"data"`
 }
@@ -45,10 +51,10 @@ type PatternRequest struct {
 type PatternResponse struct {
 	Meta Meta `json:"meta"`
 	Data struct {
-		Succeed     bool         `json:"succeed"`
-		RequestCode string       `json:"requestCode"`
-		Message     string       `json:"message"`
-		Status      string       `json:"status"`
+		Succeed     bool          `json:"succeed"`
+		RequestCode string        `json:"requestCode"`
+		Message     string        `json:"message"`
+		Status      string        `json:"status"`
 		SmsItems    []SmsItemInfo `json:"smsItems"`
 	} `json:"data"`
 }
@@ -64,10 +70,10 @@ type OTPRequest struct {
 type OTPResponse struct {
 	Meta Meta `json:"meta"`
 	Data struct {
-		Succeed     bool         `json:"succeed"`
-		RequestCode string       `json:"requestCode"`
-		Message     string       `json:"message"`
-		Status      string       `json:"status"`
+		Succeed     bool          `json:"succeed"`
+		RequestCode string        `json:"requestCode"`
+		Message     string        `json:"message"`
+		Status      string        `json:"status"`
 		SmsItems    []SmsItemInfo `json:"smsItems"`
 	} `json:"data"`
 }
@@ -76,7 +82,7 @@ type OTPResponse struct {
 type DeliveryStatusResponse struct {
 	Meta Meta `json:"meta"`
 	Data struct {
-		Status   string        `json:"status"`
+		Status   DeliveryState `json:"status"`
 		SmsItems []SmsItemInfo `json:"smsItems"`
 	} `json:"data"`
 }
@@ -89,20 +95,87 @@ type BalanceResponse struct {
 	} `json:"data"`
 }
 
+// MessageType is the kind of message a send carries, which decides the lines it may use
+type MessageType string
+
+const (
+	MessageTypeService       MessageType = "service"
+	MessageTypeAdvertisement MessageType = "advertisement"
+)
+
 // LineInfo represents a single sending line information
 type LineInfo struct {
-	Number         string `json:"Number"`
-	Description    string `json:"Description"`
-	IsDedicated    bool   `json:"IsDedicated"`
-	IsAdvertisement bool  `json:"IsAdvertisement"`
-	IsService      bool   `json:"IsService"`
-	UsableUntil    string `json:"UsableUntil"`
+	Number          string `json:"Number"`
+	Description     string `json:"Description"`
+	IsDedicated     bool   `json:"IsDedicated"`
+	IsAdvertisement bool   `json:"IsAdvertisement"`
+	IsService       bool   `json:"IsService"`
+	// UsableUntil is when the line expires; zero when it does not expire or
+	// the date is in a format that is not recognized
+	UsableUntil time.Time `json:"UsableUntil"`
+	// UsableUntilRaw keeps UsableUntil as sent
+	UsableUntilRaw string `json:"-"`
+}
+
+// UnmarshalJSON parses UsableUntil into time.Time. A date in an unknown format
+// does not fail the line; only UsableUntilRaw is set.
+func (l *LineInfo) UnmarshalJSON(data []byte) error {
+	type lineInfo LineInfo
+	var raw struct {
+		lineInfo
+		UsableUntil string `json:"UsableUntil"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*l = LineInfo(raw.lineInfo)
+
+	l.UsableUntilRaw = raw.UsableUntil
+	l.UsableUntil, _ = parseAPITime(raw.UsableUntil)
+	return nil
+}
+
+// UsableAt reports whether the line has not expired at t. A line whose expiry
+// date could not be parsed is taken as usable.
+func (l LineInfo) UsableAt(t time.Time) bool {
+	return l.UsableUntil.IsZero() || l.UsableUntil.After(t)
+}
+
+// ExpiryUnknown reports whether the line has an expiry date that could not be parsed
+func (l LineInfo) ExpiryUnknown() bool {
+	return l.UsableUntil.IsZero() && strings.TrimSpace(l.UsableUntilRaw) != ""
+}
+
+// Supports reports whether the line may send messages of the given type
+func (l LineInfo) Supports(messageType MessageType) bool {
+	switch messageType {
+	case MessageTypeService:
+		return l.IsService
+	case MessageTypeAdvertisement:
+		return l.IsAdvertisement
+	default:
+		return false
+	}
+}
+
+// LineInfos holds sending lines; the API sends either a single line or a list
+type LineInfos []LineInfo
+
+// UnmarshalJSON accepts both a single line object and an array of lines
+func (l *LineInfos) UnmarshalJSON(data []byte) error {
+	lines, err := decodeOneOrMany[LineInfo](data)
+	if err != nil {
+		return err
+	}
+	*l = lines
+	return nil
 }
 
 // LinesResponse represents the response for account lines query
 type LinesResponse struct {
 	Meta Meta      `json:"meta"`
-	Data LineInfo  `json:"data"`
+	Data LineInfos `json:"data"`
 }
 
 // PatternDetailResponse represents the response for a pattern detail query
@@ -116,26 +189,89 @@ type PatternDetailResponse struct {
 		Code             string `json:"Code"`
 		Description      string `json:"Description"`
 		ThePattern       struct {
-			Pattern               string `json:"Pattern"`
-			Status               string `json:"Status"`
-			SendingNumber        string `json:"SendingNumber"`
-			IsLockedBySendingNumber bool  `json:"IsLockedBySendingNumber"`
-			ApprovalDescription  string `json:"ApprovalDescription"`
-			Fields               []struct {
-				FieldTitle     string `json:"FieldTitle"`
-				FieldKey       string `json:"FieldKey"`
-				MaxCharacters  int    `json:"MaxCharacters"`
-				FieldType      string `json:"FieldType"`
+			Pattern                 string `json:"Pattern"`
+			Status                  string `json:"Status"`
+			SendingNumber           string `json:"SendingNumber"`
+			IsLockedBySendingNumber bool   `json:"IsLockedBySendingNumber"`
+			ApprovalDescription     string `json:"ApprovalDescription"`
+			Fields                  []struct {
+				FieldTitle    string `json:"FieldTitle"`
+				FieldKey      string `json:"FieldKey"`
+				MaxCharacters int    `json:"MaxCharacters"`
+				FieldType     string `json:"FieldType"`
 			} `json:"GetMessagePatternsByIdResponseField"`
 		} `json:"ThePattern"`
 		SettingInfo struct {
-			Website            string `json:"Website"`
+			Website             string `json:"Website"`
 			AverageSendingCount int    `json:"AverageSendingCount"`
 		} `json:"SettingInfo"`
-		Patterns    []struct {
+		Patterns []struct {
 			Pattern string `json:"Pattern"`
 			Status  string `json:"Status"`
 		} `json:"Patterns"`
 		CreateDate string `json:"CreateDate"`
 	} `json:"data"`
 }
+
+// Inbox message statuses accepted by the inbox query
+const (
+	InboxStatusNew = "New"
+)
+
+// InboxMessage represents a message received on one of the account's lines
+type InboxMessage struct {
+	Id                 string    `json:"Id"`
+	CreateDate         time.Time `json:"CreateDate"`
+	Status             string    `json:"status"`
+	ReceiveId          int64     `json:"ReceiveId"`
+	SourceId           int64     `json:"SourceId"`
+	SourceAddress      string    `json:"SourceAddress"`
+	DestinationAddress string    `json:"DestinationAddress"`
+	MessageText        string    `json:"MessageText"`
+	ReceiveDateTime    time.Time `json:"ReceiveDateTime"`
+
+	// CreateDateRaw and ReceiveDateTimeRaw keep the timestamps as sent; the
+	// parsed fields are left zero when the format is not recognized
+	CreateDateRaw      string `json:"-"`
+	ReceiveDateTimeRaw string `json:"-"`
+}
+
+// UnmarshalJSON parses the message timestamps into time.Time. A timestamp in
+// an unknown format does not fail the message; only its raw field is set.
+func (m *InboxMessage) UnmarshalJSON(data []byte) error {
+	type inboxMessage InboxMessage
+	var raw struct {
+		inboxMessage
+		CreateDate      string `json:"CreateDate"`
+		ReceiveDateTime string `json:"ReceiveDateTime"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = InboxMessage(raw.inboxMessage)
+
+	m.CreateDateRaw, m.ReceiveDateTimeRaw = raw.CreateDate, raw.ReceiveDateTime
+	m.CreateDate, _ = parseAPITime(raw.CreateDate)
+	m.ReceiveDateTime, _ = parseAPITime(raw.ReceiveDateTime)
+	return nil
+}
+
+// InboxMessages holds inbox messages; the API sends either a single message or a list
+type InboxMessages []InboxMessage
+
+// UnmarshalJSON accepts both a single message object and an array of messages
+func (m *InboxMessages) UnmarshalJSON(data []byte) error {
+	messages, err := decodeOneOrMany[InboxMessage](data)
+	if err != nil {
+		return err
+	}
+	*m = messages
+	return nil
+}
+
+// InboxResponse represents the response for an inbox query
+type InboxResponse struct {
+	Meta Meta          `json:"meta"`
+	Data InboxMessages `json:"data"`
+}
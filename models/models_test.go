@@ -0,0 +1,82 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestInboxMessagesDecodeObjectOrArray(t *testing.T) {
+	var single, list InboxResponse
+	if err := json.Unmarshal([]byte(`{"data":{"Id":"1","MessageText":"hi"}}`), &single); err != nil {
+		t.Fatalf("single: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"data":[{"Id":"1"},{"Id":"2"}]}`), &list); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(single.Data) != 1 || single.Data[0].MessageText != "hi" || len(list.Data) != 2 {
+		t.Errorf("single = %+v, list = %+v", single.Data, list.Data)
+	}
+}
+
+func TestInboxMessageTimestamps(t *testing.T) {
+	var msg InboxMessage
+	err := json.Unmarshal([]byte(`{"Id":"1","CreateDate":"2024-03-01T10:20:30","ReceiveDateTime":"1402/12/11 10:20"}`), &msg)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if want := time.Date(2024, 3, 1, 10, 20, 30, 0, TimeLocation()); !msg.CreateDate.Equal(want) {
+		t.Errorf("CreateDate = %v, want %v", msg.CreateDate, want)
+	}
+	if !msg.ReceiveDateTime.IsZero() {
+		t.Errorf("ReceiveDateTime = %v, want zero for an unknown format", msg.ReceiveDateTime)
+	}
+	if msg.ReceiveDateTimeRaw != "1402/12/11 10:20" {
+		t.Errorf("ReceiveDateTimeRaw = %q", msg.ReceiveDateTimeRaw)
+	}
+}
+
+func TestLinesDecodeObjectOrArray(t *testing.T) {
+	var single, list LinesResponse
+	if err := json.Unmarshal([]byte(`{"data":{"Number":"3000","UsableUntil":"2030-01-02T03:04:05"}}`), &single); err != nil {
+		t.Fatalf("single: %v", err)
+	}
+	if err := json.Unmarshal([]byte(`{"data":[{"Number":"1"},{"Number":"2","UsableUntil":null}]}`), &list); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(single.Data) != 1 || single.Data[0].UsableUntil.Year() != 2030 {
+		t.Errorf("single = %+v", single.Data)
+	}
+	if len(list.Data) != 2 || !list.Data[1].UsableUntil.IsZero() || list.Data[1].ExpiryUnknown() {
+		t.Errorf("list = %+v", list.Data)
+	}
+}
+
+func TestLineWithUnknownDateFormat(t *testing.T) {
+	var resp LinesResponse
+	err := json.Unmarshal([]byte(`{"data":[{"Number":"1","UsableUntil":"1409/01/01"},{"Number":"2"}]}`), &resp)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	line := resp.Data[0]
+	if !line.UsableUntil.IsZero() || line.UsableUntilRaw != "1409/01/01" || !line.ExpiryUnknown() {
+		t.Errorf("line = %+v, want a zero UsableUntil with the raw date kept", line)
+	}
+}
+
+func TestTimestampsWithoutOffsetAreIranTime(t *testing.T) {
+	tests := map[string]time.Time{
+		"2024-03-01T10:20:30":       time.Date(2024, 3, 1, 6, 50, 30, 0, time.UTC),
+		"2024-03-01 10:20:30":       time.Date(2024, 3, 1, 6, 50, 30, 0, time.UTC),
+		"2024-03-01T10:20:30Z":      time.Date(2024, 3, 1, 10, 20, 30, 0, time.UTC),
+		"2024-03-01T10:20:30+01:00": time.Date(2024, 3, 1, 9, 20, 30, 0, time.UTC),
+	}
+	for value, want := range tests {
+		got, err := parseAPITime(value)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseAPITime(%q) = %v, %v, want %v", value, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// apiLocation is Iran time, which timestamps without a UTC offset are read in
+var apiLocation = loadAPILocation()
+
+// loadAPILocation loads Asia/Tehran, falling back to a fixed +03:30 offset
+// when the system has no time zone database
+func loadAPILocation() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Tehran"); err == nil {
+		return loc
+	}
+	return time.FixedZone("+0330", 3*60*60+30*60)
+}
+
+// TimeLocation returns the location API timestamps without a UTC offset are read in
+func TimeLocation() *time.Location {
+	return apiLocation
+}
+
+// apiTimeLayouts are the timestamp formats seen in API responses
+var apiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseAPITime parses an API timestamp; an empty value gives the zero time
+func parseAPITime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range apiTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, apiLocation); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time format %q", value)
+}
+
+// decodeOneOrMany decodes data holding either a single object or an array of objects
+func decodeOneOrMany[T any](data []byte) ([]T, error) {
+	trimmed := strings.TrimSpace(string(data))
+	switch {
+	case trimmed == "" || trimmed == "null":
+		return nil, nil
+	case strings.HasPrefix(trimmed, "["):
+		var items []T
+		if err := json.Unmarshal(data, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	default:
+		var item T
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+		return []T{item}, nil
+	}
+}
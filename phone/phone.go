@@ -0,0 +1,175 @@
+// Package phone normalizes and validates Iranian mobile numbers.
+//
+// Numbers written as 0912..., 912..., +98912..., 0098912... or 98912..., with
+// ASCII, Persian or Arabic-Indic digits, all normalize to the canonical local
+// form 09121234567 that Mediana accepts.
+package phone
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/AryanHamedani/mediana-go-sdk/internal/persian"
+)
+
+// Reason explains why a number was rejected
+type Reason string
+
+const (
+	ReasonEmpty         Reason = "empty number"
+	ReasonInvalidChars  Reason = "contains characters other than digits"
+	ReasonForeign       Reason = "not an Iranian number"
+	ReasonLandline      Reason = "landline number, not a mobile number"
+	ReasonLength        Reason = "wrong number of digits"
+	ReasonUnknownPrefix Reason = "unknown mobile prefix"
+)
+
+// InvalidNumberError reports a number that is not a valid Iranian mobile number
+type InvalidNumberError struct {
+	Number string
+	Reason Reason
+}
+
+func (e *InvalidNumberError) Error() string {
+	return fmt.Sprintf("invalid mobile number %q: %s", e.Number, e.Reason)
+}
+
+// Operator is the mobile operator a prefix is assigned to
+type Operator string
+
+const (
+	OperatorMCI      Operator = "MCI"
+	OperatorIrancell Operator = "Irancell"
+	OperatorRightel  Operator = "Rightel"
+	OperatorMVNO     Operator = "MVNO"
+)
+
+// prefixes maps the first four digits of a mobile number to its operator
+var prefixes = map[string]Operator{
+	"0910": OperatorMCI, "0911": OperatorMCI, "0912": OperatorMCI, "0913": OperatorMCI,
+	"0914": OperatorMCI, "0915": OperatorMCI, "0916": OperatorMCI, "0917": OperatorMCI,
+	"0918": OperatorMCI, "0919": OperatorMCI, "0990": OperatorMCI, "0991": OperatorMCI,
+	"0992": OperatorMCI, "0993": OperatorMCI, "0994": OperatorMCI, "0996": OperatorMCI,
+
+	"0900": OperatorIrancell, "0901": OperatorIrancell, "0902": OperatorIrancell,
+	"0903": OperatorIrancell, "0904": OperatorIrancell, "0905": OperatorIrancell,
+	"0930": OperatorIrancell, "0933": OperatorIrancell, "0935": OperatorIrancell,
+	"0936": OperatorIrancell, "0937": OperatorIrancell, "0938": OperatorIrancell,
+	"0939": OperatorIrancell, "0941": OperatorIrancell,
+
+	"0920": OperatorRightel, "0921": OperatorRightel, "0922": OperatorRightel, "0923": OperatorRightel,
+
+	"0931": OperatorMVNO, "0932": OperatorMVNO, "0934": OperatorMCI,
+	"0998": OperatorMVNO, "0999": OperatorMVNO,
+}
+
+// separators are characters people put inside numbers that carry no meaning
+var separators = strings.NewReplacer(
+	" ", "", "-", "", ".", "", "(", "", ")", "",
+	"\u00a0", "", // no-break space
+	"\u200c", "", // zero-width non-joiner
+	"\u200e", "", "\u200f", "", "\u202a", "", "\u202c", "", // direction marks
+)
+
+// Normalize returns number in the canonical form 09XXXXXXXXX, or an
+// *InvalidNumberError explaining why it is not an Iranian mobile number
+func Normalize(number string) (string, error) {
+	invalid := func(reason Reason) (string, error) {
+		return "", &InvalidNumberError{Number: number, Reason: reason}
+	}
+
+	digits := separators.Replace(persian.NormalizeDigits(strings.TrimSpace(number)))
+	if digits == "" {
+		return invalid(ReasonEmpty)
+	}
+
+	international := false
+	switch {
+	case strings.HasPrefix(digits, "+"):
+		digits, international = digits[1:], true
+	case strings.HasPrefix(digits, "00"):
+		digits, international = digits[2:], true
+	}
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return invalid(ReasonInvalidChars)
+		}
+	}
+
+	// national is the number without its trunk or country prefix
+	var national string
+	switch {
+	case international:
+		if !strings.HasPrefix(digits, "98") {
+			return invalid(ReasonForeign)
+		}
+		national = digits[2:]
+	case len(digits) == 12 && strings.HasPrefix(digits, "98"):
+		national = digits[2:]
+	case strings.HasPrefix(digits, "0"):
+		national = digits[1:]
+	default:
+		national = digits
+	}
+	national = strings.TrimPrefix(national, "0") // +98 0912... is a common mix-up
+
+	if len(national) != 10 {
+		return invalid(ReasonLength)
+	}
+	if national[0] != '9' {
+		return invalid(ReasonLandline)
+	}
+
+	local := "0" + national
+	if _, ok := prefixes[local[:4]]; !ok {
+		return invalid(ReasonUnknownPrefix)
+	}
+	return local, nil
+}
+
+// Validate returns an *InvalidNumberError when number is not an Iranian mobile number
+func Validate(number string) error {
+	_, err := Normalize(number)
+	return err
+}
+
+// E164 returns number in international form, +989XXXXXXXXX
+func E164(number string) (string, error) {
+	local, err := Normalize(number)
+	if err != nil {
+		return "", err
+	}
+	return "+98" + local[1:], nil
+}
+
+// OperatorOf returns the operator that number's prefix is assigned to
+func OperatorOf(number string) (Operator, error) {
+	local, err := Normalize(number)
+	if err != nil {
+		return "", err
+	}
+	return prefixes[local[:4]], nil
+}
+
+// NormalizeList normalizes numbers and drops duplicates, keeping the first
+// occurrence of each. Every invalid number is reported in the returned error,
+// which joins their *InvalidNumberError values; the valid numbers are still returned.
+func NormalizeList(numbers []string) ([]string, error) {
+	seen := make(map[string]bool, len(numbers))
+	normalized := make([]string, 0, len(numbers))
+	var errs []error
+	for _, number := range numbers {
+		local, err := Normalize(number)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if seen[local] {
+			continue
+		}
+		seen[local] = true
+		normalized = append(normalized, local)
+	}
+	return normalized, stderrors.Join(errs...)
+}
@@ -0,0 +1,113 @@
+package phone
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"local", "09121234567"},
+		{"without trunk zero", "9121234567"},
+		{"plus country code", "+989121234567"},
+		{"double zero country code", "00989121234567"},
+		{"bare country code", "989121234567"},
+		{"country code and trunk zero", "+98 0912 123 4567"},
+		{"persian digits", "۰۹۱۲۱۲۳۴۵۶۷"},
+		{"arabic-indic digits", "٠٩١٢١٢٣٤٥٦٧"},
+		{"separators", " 0912-123 (4567) "},
+		{"direction marks", "‪+98 912 123 4567‬"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.input)
+			if err != nil || got != "09121234567" {
+				t.Errorf("Normalize(%q) = %q, %v, want 09121234567", tt.input, got, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeRejects(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Reason
+	}{
+		{"", ReasonEmpty},
+		{"  ", ReasonEmpty},
+		{"0912123456a", ReasonInvalidChars},
+		{"0912/1234567", ReasonInvalidChars},
+		{"+14155552671", ReasonForeign},
+		{"00442079460000", ReasonForeign},
+		{"02112345678", ReasonLandline},
+		{"+982112345678", ReasonLandline},
+		{"0912123456", ReasonLength},
+		{"091212345678", ReasonLength},
+		{"09501234567", ReasonUnknownPrefix},
+		{"09971234567", ReasonUnknownPrefix},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Normalize(tt.input)
+			var invalid *InvalidNumberError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Normalize(%q) error = %v, want an InvalidNumberError", tt.input, err)
+			}
+			if invalid.Reason != tt.want || invalid.Number != tt.input {
+				t.Errorf("Normalize(%q) rejected %q for %q, want %q", tt.input, invalid.Number, invalid.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestE164AndOperatorOf(t *testing.T) {
+	if got, err := E164("۰۹۳۵۱۲۳۴۵۶۷"); err != nil || got != "+989351234567" {
+		t.Errorf("E164() = %q, %v, want +989351234567", got, err)
+	}
+
+	operators := map[string]Operator{
+		"09121234567": OperatorMCI,
+		"09351234567": OperatorIrancell,
+		"09211234567": OperatorRightel,
+		"09991234567": OperatorMVNO,
+	}
+	for number, want := range operators {
+		if got, err := OperatorOf(number); err != nil || got != want {
+			t.Errorf("OperatorOf(%q) = %q, %v, want %q", number, got, err, want)
+		}
+	}
+	if _, err := OperatorOf("02112345678"); err == nil {
+		t.Error("OperatorOf() of a landline returned no error")
+	}
+}
+
+func TestNormalizeList(t *testing.T) {
+	got, err := NormalizeList([]string{
+		"09121234567", "02112345678", "+989351234567", "9121234567", "0912", "۰۹۳۵۱۲۳۴۵۶۷",
+	})
+
+	want := []string{"09121234567", "09351234567"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeList() = %v, want %v", got, want)
+	}
+
+	var reasons []Reason
+	for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var invalid *InvalidNumberError
+		if !errors.As(err, &invalid) {
+			t.Fatalf("joined error %v is not an InvalidNumberError", err)
+		}
+		reasons = append(reasons, invalid.Reason)
+	}
+	if !reflect.DeepEqual(reasons, []Reason{ReasonLandline, ReasonLength}) {
+		t.Errorf("rejected for %v, want the landline and the short number", reasons)
+	}
+
+	if got, err := NormalizeList([]string{"09121234567"}); err != nil || len(got) != 1 {
+		t.Errorf("NormalizeList() of valid numbers = %v, %v", got, err)
+	}
+}
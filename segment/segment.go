@@ -0,0 +1,144 @@
+// Package segment works out how many billable SMS parts a message text uses.
+//
+// Text that fits the GSM 03.38 alphabet is sent as GSM-7: 160 characters in a
+// single part or 153 per part once concatenated, and characters from the
+// extension table (such as € or {) take two. Any other character, including
+// every Persian letter, makes the whole text UCS-2: 70 characters in a single
+// part or 67 per part, with characters outside the BMP taking two.
+package segment
+
+import "fmt"
+
+// Encoding is the character encoding a message is sent with
+type Encoding string
+
+const (
+	GSM7 Encoding = "GSM-7"
+	UCS2 Encoding = "UCS-2"
+)
+
+// Part limits, in encoding units, for single and concatenated messages
+const (
+	GSM7SingleLimit    = 160
+	GSM7MultipartLimit = 153
+	UCS2SingleLimit    = 70
+	UCS2MultipartLimit = 67
+)
+
+// gsm7Basic is the GSM 03.38 default alphabet, without the escape character
+const gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+	"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+// gsm7Extended is the GSM 03.38 extension table; each character is sent as an escape and the character
+const gsm7Extended = "\f^{}\\[~]|€"
+
+var (
+	basicSet    = runeSet(gsm7Basic)
+	extendedSet = runeSet(gsm7Extended)
+)
+
+func runeSet(chars string) map[rune]bool {
+	set := make(map[rune]bool)
+	for _, r := range chars {
+		set[r] = true
+	}
+	return set
+}
+
+// Info describes how a message text is split into parts
+type Info struct {
+	Encoding Encoding
+	// Characters is the number of characters in the text
+	Characters int
+	// Units is the length in encoding units: septets for GSM-7, UTF-16 code units for UCS-2
+	Units int
+	// ExtendedChars counts the GSM-7 extension table characters, which take two units each
+	ExtendedChars int
+	// Segments is the number of billable parts
+	Segments int
+	// PerSegment is the number of units that fit in one part
+	PerSegment int
+	// Remaining is the number of units still free in the last part
+	Remaining int
+}
+
+// Calculate returns the encoding and part count of text. An empty text has no parts.
+func Calculate(text string) Info {
+	info := Info{Encoding: GSM7}
+
+	var sizes []int
+	for _, r := range text {
+		info.Characters++
+		switch {
+		case basicSet[r]:
+			sizes = append(sizes, 1)
+		case extendedSet[r]:
+			info.ExtendedChars++
+			sizes = append(sizes, 2)
+		default:
+			info.Encoding = UCS2
+			sizes = append(sizes, 0)
+		}
+	}
+
+	single, multipart := GSM7SingleLimit, GSM7MultipartLimit
+	if info.Encoding == UCS2 {
+		single, multipart = UCS2SingleLimit, UCS2MultipartLimit
+		info.ExtendedChars = 0
+		sizes = sizes[:0]
+		for _, r := range text {
+			size := 1
+			if r > 0xFFFF {
+				size = 2 // a UTF-16 surrogate pair
+			}
+			sizes = append(sizes, size)
+		}
+	}
+
+	for _, size := range sizes {
+		info.Units += size
+	}
+
+	if info.Units <= single {
+		info.PerSegment = single
+		info.Remaining = single - info.Units
+		if info.Units > 0 {
+			info.Segments = 1
+		}
+		return info
+	}
+
+	// An escaped GSM-7 character or a UTF-16 surrogate pair is never split
+	// between two parts, so a part may end a unit short
+	info.PerSegment = multipart
+	used := 0
+	info.Segments = 1
+	for _, size := range sizes {
+		if used+size > multipart {
+			info.Segments++
+			used = 0
+		}
+		used += size
+	}
+	info.Remaining = multipart - used
+	return info
+}
+
+// TooLongError reports a text that needs more parts than allowed
+type TooLongError struct {
+	Info        Info
+	MaxSegments int
+}
+
+func (e *TooLongError) Error() string {
+	return fmt.Sprintf("message text needs %d %s parts, more than the limit of %d", e.Info.Segments, e.Info.Encoding, e.MaxSegments)
+}
+
+// Check returns a *TooLongError when text needs more than maxSegments parts
+func Check(text string, maxSegments int) error {
+	info := Calculate(text)
+	if info.Segments > maxSegments {
+		return &TooLongError{Info: info, MaxSegments: maxSegments}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package segment
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		encoding  Encoding
+		units     int
+		segments  int
+		remaining int
+	}{
+		{"empty", "", GSM7, 0, 0, 160},
+		{"160 GSM-7", strings.Repeat("a", 160), GSM7, 160, 1, 0},
+		{"161 GSM-7", strings.Repeat("a", 161), GSM7, 161, 2, 145},
+		{"70 UCS-2", strings.Repeat("س", 70), UCS2, 70, 1, 0},
+		{"71 UCS-2", strings.Repeat("س", 71), UCS2, 71, 2, 63},
+		{"one Persian letter makes it UCS-2", strings.Repeat("a", 69) + "س", UCS2, 70, 1, 0},
+		{"80 extension chars", strings.Repeat("€", 80), GSM7, 160, 1, 0},
+		{"81 extension chars", strings.Repeat("€", 81), GSM7, 162, 2, 143},
+		// Part 1 ends a unit short so € is not split: 152 + (2+151) + 1
+		{"escape not split", strings.Repeat("a", 152) + "€" + strings.Repeat("a", 152), GSM7, 306, 3, 152},
+		{"35 surrogate pairs", strings.Repeat("😀", 35), UCS2, 70, 1, 0},
+		// 33 pairs fill part 1 (66 units), 3 go to part 2
+		{"36 surrogate pairs", strings.Repeat("😀", 36), UCS2, 72, 2, 61},
+		// Part 1 ends a unit short so the pair is not split: 66 + (2+5)
+		{"surrogate pair not split", strings.Repeat("س", 66) + "😀" + strings.Repeat("س", 5), UCS2, 73, 2, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := Calculate(tt.text)
+			if info.Encoding != tt.encoding || info.Units != tt.units || info.Segments != tt.segments || info.Remaining != tt.remaining {
+				t.Errorf("Calculate() = %s, %d units, %d segments, %d remaining; want %s, %d, %d, %d",
+					info.Encoding, info.Units, info.Segments, info.Remaining, tt.encoding, tt.units, tt.segments, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestCalculateCountsCharacters(t *testing.T) {
+	info := Calculate("price: 10€ {ok}")
+	if info.Characters != 15 || info.ExtendedChars != 3 || info.Units != 18 {
+		t.Errorf("Calculate() = %+v, want 15 characters, 3 extended, 18 units", info)
+	}
+
+	// Extension characters cost nothing extra once the text is UCS-2
+	info = Calculate("€ سلام")
+	if info.Encoding != UCS2 || info.ExtendedChars != 0 || info.Units != 6 {
+		t.Errorf("Calculate() = %+v, want UCS-2 with 6 units", info)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(strings.Repeat("a", 306), 2); err != nil {
+		t.Errorf("Check(306 chars, 2) error = %v", err)
+	}
+
+	err := Check(strings.Repeat("a", 307), 2)
+	var tooLong *TooLongError
+	if !errors.As(err, &tooLong) || tooLong.Info.Segments != 3 || tooLong.MaxSegments != 2 {
+		t.Fatalf("Check(307 chars, 2) error = %v, want a TooLongError for 3 parts", err)
+	}
+	if got := err.Error(); got != "message text needs 3 GSM-7 parts, more than the limit of 2" {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -0,0 +1,16 @@
+module github.com/AryanHamedani/mediana-go-sdk/tracing
+
+go 1.21
+
+require (
+	github.com/AryanHamedani/mediana-go-sdk v1.2.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+)
@@ -0,0 +1,109 @@
+// Package tracing instruments the Mediana client with OpenTelemetry spans.
+package tracing
+
+import (
+	"context"
+	stderrors "errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AryanHamedani/mediana-go-sdk/client"
+	"github.com/AryanHamedani/mediana-go-sdk/errors"
+)
+
+const instrumentationName = "github.com/AryanHamedani/mediana-go-sdk/tracing"
+
+// Span attribute keys set on every call
+const (
+	OperationKey      = attribute.Key("mediana.operation")
+	ErrorCodeKey      = attribute.Key("mediana.error_code")
+	RecipientCountKey = attribute.Key("mediana.recipient_count")
+	RequestCodeKey    = attribute.Key("mediana.request_code")
+	CachedKey         = attribute.Key("mediana.cached")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// Option configures the tracing middleware
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider; the global provider is used by default
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithPropagators sets the propagators used to inject trace context into
+// outbound headers; the global propagators are used by default
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// Middleware returns a client.Middleware that wraps every call in a client span
+//
+//	c := client.New(apiKey, client.WithMiddleware(tracing.Middleware()))
+func Middleware(options ...Option) client.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		propagators:    otel.GetTextMapPropagator(),
+	}
+	for _, opt := range options {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+
+	return func(next client.Handler) client.Handler {
+		return func(ctx context.Context, call *client.Call) error {
+			ctx, span := tracer.Start(ctx, "mediana "+call.Operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(
+					OperationKey.String(call.Operation),
+					semconv.HTTPRequestMethodKey.String(call.Method),
+				),
+			)
+			defer span.End()
+
+			if count, ok := call.RecipientCount(); ok {
+				span.SetAttributes(RecipientCountKey.Int(count))
+			}
+
+			cfg.propagators.Inject(ctx, propagation.HeaderCarrier(call.Header))
+
+			err := next(ctx, call)
+
+			if call.StatusCode != 0 {
+				span.SetAttributes(semconv.HTTPResponseStatusCode(call.StatusCode))
+			}
+			if call.Cached {
+				span.SetAttributes(CachedKey.Bool(true))
+			}
+
+			if err != nil {
+				var apiErr *errors.APIError
+				if stderrors.As(err, &apiErr) && apiErr.Code != "" {
+					span.SetAttributes(ErrorCodeKey.String(apiErr.Code))
+				}
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return err
+			}
+
+			if requestCode := call.RequestCode(); requestCode != "" {
+				span.SetAttributes(RequestCodeKey.String(requestCode))
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AryanHamedani/mediana-go-sdk/client"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// newTracedClient returns a client whose spans go to the returned recorder,
+// talking to a server that answers with status and body and records the
+// traceparent header it received
+func newTracedClient(t *testing.T, status int, body string) (*client.Client, *tracetest.SpanRecorder, *string) {
+	t.Helper()
+
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := client.New("key", client.WithBaseURL(srv.URL), client.WithMiddleware(Middleware(
+		WithTracerProvider(provider),
+		WithPropagators(propagation.TraceContext{}),
+	)))
+	return c, recorder, &traceparent
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestMiddlewareRecordsSuccessfulSend(t *testing.T) {
+	c, recorder, traceparent := newTracedClient(t, http.StatusOK, `{"data":{"requestCode":"rc-1"}}`)
+
+	_, err := c.SendSMS(context.Background(), models.SMSRequest{MessageText: "hi", Recipients: []string{"09121111111", "09122222222"}})
+	if err != nil {
+		t.Fatalf("SendSMS() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "mediana sendSms" || span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span = %q (%v), want client span \"mediana sendSms\"", span.Name(), span.SpanKind())
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("status = %v, want unset", span.Status())
+	}
+
+	attrs := attributes(span)
+	if got := attrs[OperationKey].AsString(); got != client.OpSendSMS {
+		t.Errorf("%s = %q, want %q", OperationKey, got, client.OpSendSMS)
+	}
+	if got := attrs[RecipientCountKey].AsInt64(); got != 2 {
+		t.Errorf("%s = %d, want 2", RecipientCountKey, got)
+	}
+	if got := attrs[RequestCodeKey].AsString(); got != "rc-1" {
+		t.Errorf("%s = %q, want rc-1", RequestCodeKey, got)
+	}
+	if got := attrs["http.response.status_code"].AsInt64(); got != http.StatusOK {
+		t.Errorf("http.response.status_code = %d, want 200", got)
+	}
+
+	want := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-01"
+	if *traceparent != want {
+		t.Errorf("traceparent header = %q, want %q", *traceparent, want)
+	}
+}
+
+func TestMiddlewareRecordsAPIError(t *testing.T) {
+	c, recorder, _ := newTracedClient(t, http.StatusBadRequest, `{"meta":{"code":"1041","errorMessage":"invalid recipient"}}`)
+
+	_, err := c.SendSMS(context.Background(), models.SMSRequest{MessageText: "hi", Recipients: []string{"09121111111"}})
+	if err == nil {
+		t.Fatal("SendSMS() error = nil, want the API error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("ended spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Status().Code != codes.Error {
+		t.Errorf("status = %v, want error", span.Status())
+	}
+	attrs := attributes(span)
+	if got := attrs[ErrorCodeKey].AsString(); got != "1041" {
+		t.Errorf("%s = %q, want 1041", ErrorCodeKey, got)
+	}
+	if got := attrs["http.response.status_code"].AsInt64(); got != http.StatusBadRequest {
+		t.Errorf("http.response.status_code = %d, want 400", got)
+	}
+	if _, ok := attrs[RequestCodeKey]; ok {
+		t.Errorf("%s set on a failed call", RequestCodeKey)
+	}
+	if len(span.Events()) == 0 || span.Events()[0].Name != "exception" {
+		t.Errorf("events = %v, want the recorded error", span.Events())
+	}
+}
@@ -0,0 +1,219 @@
+// Package webhook receives delivery reports and inbound messages pushed by
+// Mediana and dispatches them, together with polled updates, as Events.
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/AryanHamedani/mediana-go-sdk/inbox"
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// EventType tells what an Event carries
+type EventType string
+
+const (
+	// EventDeliveryReport carries the state of a single SMS item
+	EventDeliveryReport EventType = "delivery_report"
+	// EventInboundMessage carries a message received on one of the account's lines
+	EventInboundMessage EventType = "inbound_message"
+)
+
+// Event is a delivery report or inbound message, whether it was pushed to the
+// Handler or found by polling
+type Event struct {
+	ID          string               `json:"id,omitempty"`
+	Type        EventType            `json:"type"`
+	RequestCode string               `json:"requestCode,omitempty"`
+	SmsItem     *models.SmsItemInfo  `json:"smsItem,omitempty"`
+	Message     *models.InboxMessage `json:"message,omitempty"`
+}
+
+// Key identifies the event for deduplication: its ID, or one derived from its content
+func (e Event) Key() string {
+	if e.ID != "" {
+		return e.ID
+	}
+	switch {
+	case e.SmsItem != nil:
+		return fmt.Sprintf("%s:%s:%s:%s", e.Type, e.RequestCode, e.SmsItem.SmsItemId, e.SmsItem.Status)
+	case e.Message != nil:
+		return fmt.Sprintf("%s:%s", e.Type, inbox.MessageKey(*e.Message))
+	default:
+		return ""
+	}
+}
+
+// validate checks that the event carries the payload its type needs
+func (e Event) validate() error {
+	switch e.Type {
+	case EventDeliveryReport:
+		if e.SmsItem == nil {
+			return fmt.Errorf("delivery report without smsItem")
+		}
+	case EventInboundMessage:
+		if e.Message == nil {
+			return fmt.Errorf("inbound message without message")
+		}
+	default:
+		return fmt.Errorf("unknown event type %q", e.Type)
+	}
+	return nil
+}
+
+// EventHandler handles a dispatched event
+type EventHandler func(ctx context.Context, event Event) error
+
+// Dispatcher delivers every event once to the callbacks registered for its type
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[EventType][]EventHandler
+	seen     inbox.SeenStore
+	onError  func(Event, error)
+
+	keysMu sync.Mutex
+	keys   map[string]*keyLock
+}
+
+// keyLock serializes the dispatches of one event key
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// DispatcherOption configures a Dispatcher
+type DispatcherOption func(*Dispatcher)
+
+// WithSeenStore sets the store used to drop replayed events
+func WithSeenStore(store inbox.SeenStore) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.seen = store
+	}
+}
+
+// WithErrorHandler is called when dispatching a polled event fails, since
+// pollers have no caller to return the error to
+func WithErrorHandler(onError func(Event, error)) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.onError = onError
+	}
+}
+
+// NewDispatcher creates a dispatcher that remembers the last 10000 events in memory
+func NewDispatcher(options ...DispatcherOption) *Dispatcher {
+	d := &Dispatcher{
+		handlers: make(map[EventType][]EventHandler),
+		seen:     inbox.NewMemorySeenStore(0),
+		keys:     make(map[string]*keyLock),
+	}
+
+	for _, opt := range options {
+		opt(d)
+	}
+
+	return d
+}
+
+// On registers handler for every event of the given type
+func (d *Dispatcher) On(eventType EventType, handler EventHandler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[eventType] = append(d.handlers[eventType], handler)
+}
+
+// OnDeliveryReport registers handler for delivery reports
+func (d *Dispatcher) OnDeliveryReport(handler func(ctx context.Context, requestCode string, item models.SmsItemInfo) error) {
+	d.On(EventDeliveryReport, func(ctx context.Context, event Event) error {
+		return handler(ctx, event.RequestCode, *event.SmsItem)
+	})
+}
+
+// OnInboundMessage registers handler for inbound messages
+func (d *Dispatcher) OnInboundMessage(handler func(ctx context.Context, msg models.InboxMessage) error) {
+	d.On(EventInboundMessage, func(ctx context.Context, event Event) error {
+		return handler(ctx, *event.Message)
+	})
+}
+
+// Dispatch runs the handlers registered for the event, unless the same event
+// was already dispatched. The event is only remembered once every handler
+// succeeded. Concurrent dispatches of the same event run one after the other,
+// so a replay that arrives while the first delivery is being handled waits for
+// it and is then dropped. This holds within one Dispatcher; dispatchers in
+// several processes sharing a SeenStore may still both handle a replay.
+func (d *Dispatcher) Dispatch(ctx context.Context, event Event) error {
+	if err := event.validate(); err != nil {
+		return err
+	}
+
+	key := event.Key()
+	unlock := d.lockKey(key)
+	defer unlock()
+
+	seen, err := d.seen.Seen(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to read seen store: %w", err)
+	}
+	if seen {
+		return nil
+	}
+
+	d.mu.RLock()
+	handlers := d.handlers[event.Type]
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	if err := d.seen.MarkSeen(ctx, key); err != nil {
+		return fmt.Errorf("failed to write seen store: %w", err)
+	}
+	return nil
+}
+
+// lockKey locks key against other dispatches and returns the unlock function
+func (d *Dispatcher) lockKey(key string) func() {
+	d.keysMu.Lock()
+	lock, ok := d.keys[key]
+	if !ok {
+		lock = &keyLock{}
+		d.keys[key] = lock
+	}
+	lock.refs++
+	d.keysMu.Unlock()
+
+	lock.mu.Lock()
+	return func() {
+		lock.mu.Unlock()
+
+		d.keysMu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(d.keys, key)
+		}
+		d.keysMu.Unlock()
+	}
+}
+
+// HandleInboxMessage dispatches a polled inbox message. It plugs the
+// dispatcher into an inbox.Poller:
+//
+//	poller.Run(ctx, dispatcher.HandleInboxMessage)
+func (d *Dispatcher) HandleInboxMessage(ctx context.Context, msg models.InboxMessage) {
+	d.dispatchPolled(ctx, Event{Type: EventInboundMessage, Message: &msg})
+}
+
+// dispatchPolled dispatches an event found by polling and reports a failure to the error handler
+func (d *Dispatcher) dispatchPolled(ctx context.Context, event Event) error {
+	err := d.Dispatch(ctx, event)
+	if err != nil && d.onError != nil {
+		d.onError(event, err)
+	}
+	return err
+}
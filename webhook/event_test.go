@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+func deliveryReport(id string, status models.DeliveryState) Event {
+	return Event{Type: EventDeliveryReport, RequestCode: "rc1", SmsItem: &models.SmsItemInfo{SmsItemId: id, Status: status}}
+}
+
+func TestDispatchDropsConcurrentReplays(t *testing.T) {
+	d := NewDispatcher()
+	var calls int32
+	d.OnDeliveryReport(func(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(10 * time.Millisecond) // a slow handler, so replays overlap it
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := d.Dispatch(context.Background(), deliveryReport("1", models.StateDelivered)); err != nil {
+				t.Errorf("Dispatch() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("handler calls = %d, want 1", got)
+	}
+	if len(d.keys) != 0 {
+		t.Errorf("%d key locks left behind", len(d.keys))
+	}
+}
+
+func TestDispatchRetriesFailedEvent(t *testing.T) {
+	d := NewDispatcher()
+	var calls int
+	d.OnDeliveryReport(func(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+		calls++
+		if calls == 1 {
+			return errors.New("database down")
+		}
+		return nil
+	})
+
+	event := deliveryReport("1", models.StateDelivered)
+	if err := d.Dispatch(context.Background(), event); err == nil {
+		t.Fatal("first Dispatch() error = nil, want the handler error")
+	}
+	for i := 0; i < 2; i++ {
+		if err := d.Dispatch(context.Background(), event); err != nil {
+			t.Fatalf("Dispatch() error = %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2", calls)
+	}
+}
+
+func TestDispatchSeparatesStateChanges(t *testing.T) {
+	d := NewDispatcher()
+	var states []models.DeliveryState
+	d.OnDeliveryReport(func(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+		states = append(states, item.Status)
+		return nil
+	})
+
+	for _, status := range []models.DeliveryState{models.StateSent, models.StateSent, models.StateDelivered} {
+		d.Dispatch(context.Background(), deliveryReport("1", status))
+	}
+	if len(states) != 2 {
+		t.Errorf("states = %v, want [Sent Delivered]", states)
+	}
+}
@@ -0,0 +1,157 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of
+	// the timestamp, a dot and the request body
+	SignatureHeader = "X-Mediana-Signature"
+	// TimestampHeader carries the Unix time the callback was signed at
+	TimestampHeader = "X-Mediana-Timestamp"
+
+	defaultTolerance = 5 * time.Minute
+	maxBodySize      = 1 << 20
+)
+
+// Sign returns the SignatureHeader value for body signed at timestamp
+func Sign(secret []byte, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	fmt.Fprintf(mac, "%d.", timestamp.Unix())
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Handler is an http.Handler receiving delivery-report and inbound-message
+// callbacks. The body is a single Event or an array of Events, signed with
+// the shared secret. Verified events are passed to the dispatcher, which
+// drops replays.
+//
+// The Mediana API documents no callbacks, so the SignatureHeader and
+// TimestampHeader scheme (see Sign) is defined by this SDK: whatever relays
+// the callbacks, such as a gateway or LocalSource, must sign them this way.
+type Handler struct {
+	secret     []byte
+	dispatcher *Dispatcher
+	tolerance  time.Duration
+	now        func() time.Time
+}
+
+// HandlerOption configures a Handler
+type HandlerOption func(*Handler)
+
+// WithTolerance sets how far the callback timestamp may be from the current
+// time. Older callbacks are rejected as replays. Defaults to 5 minutes.
+func WithTolerance(tolerance time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.tolerance = tolerance
+	}
+}
+
+// NewHandler creates a handler verifying callbacks with secret
+func NewHandler(secret []byte, dispatcher *Dispatcher, options ...HandlerOption) *Handler {
+	h := &Handler{
+		secret:     secret,
+		dispatcher: dispatcher,
+		tolerance:  defaultTolerance,
+		now:        time.Now,
+	}
+
+	for _, opt := range options {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.verify(r.Header, body); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	events, err := decodeEvents(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, event := range events {
+		if err := h.dispatcher.Dispatch(r.Context(), event); err != nil {
+			// A failed callback is retried by the sender; events already handled are deduplicated
+			http.Error(w, "failed to handle event", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verify checks the signature and timestamp headers against body
+func (h *Handler) verify(header http.Header, body []byte) error {
+	unix, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid %s header", TimestampHeader)
+	}
+
+	timestamp := time.Unix(unix, 0)
+	if h.tolerance > 0 {
+		if skew := h.now().Sub(timestamp); skew > h.tolerance || skew < -h.tolerance {
+			return fmt.Errorf("timestamp outside tolerance")
+		}
+	}
+
+	expected := Sign(h.secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(header.Get(SignatureHeader))) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// decodeEvents reads a single event or an array of events
+func decodeEvents(body []byte) ([]Event, error) {
+	trimmed := bytes.TrimSpace(body)
+
+	var events []Event
+	if strings.HasPrefix(string(trimmed), "[") {
+		if err := json.Unmarshal(trimmed, &events); err != nil {
+			return nil, fmt.Errorf("invalid events: %w", err)
+		}
+	} else {
+		var event Event
+		if err := json.Unmarshal(trimmed, &event); err != nil {
+			return nil, fmt.Errorf("invalid event: %w", err)
+		}
+		events = []Event{event}
+	}
+
+	for _, event := range events {
+		if err := event.validate(); err != nil {
+			return nil, fmt.Errorf("invalid event: %w", err)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+func TestHandlerDispatchesSignedCallbacks(t *testing.T) {
+	d := NewDispatcher()
+	var got []string
+	d.OnDeliveryReport(func(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+		got = append(got, requestCode+"/"+item.SmsItemId)
+		return nil
+	})
+	source := NewLocalSource(NewHandler([]byte("secret"), d), []byte("secret"))
+
+	item := models.SmsItemInfo{SmsItemId: "1", Status: models.StateDelivered}
+	if err := source.PushDeliveryReport(context.Background(), "rc1", item); err != nil {
+		t.Fatalf("PushDeliveryReport() error = %v", err)
+	}
+	if err := source.PushDeliveryReport(context.Background(), "rc1", item); err != nil {
+		t.Fatalf("replayed PushDeliveryReport() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "rc1/1" {
+		t.Errorf("dispatched = %v, want [rc1/1]", got)
+	}
+}
+
+func TestHandlerRejectsWrongSecret(t *testing.T) {
+	d := NewDispatcher()
+	source := NewLocalSource(NewHandler([]byte("secret"), d), []byte("other"))
+
+	err := source.PushDeliveryReport(context.Background(), "rc1", models.SmsItemInfo{SmsItemId: "1", Status: models.StateSent})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("PushDeliveryReport() error = %v, want a 401 rejection", err)
+	}
+}
+
+func TestHandlerReportsHandlerFailure(t *testing.T) {
+	d := NewDispatcher()
+	d.OnDeliveryReport(func(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+		return errors.New("database down")
+	})
+	source := NewLocalSource(NewHandler([]byte("secret"), d), []byte("secret"))
+
+	err := source.PushDeliveryReport(context.Background(), "rc1", models.SmsItemInfo{SmsItemId: "1", Status: models.StateSent})
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("PushDeliveryReport() error = %v, want a 500 rejection", err)
+	}
+}
@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// StatusGetter looks up delivery statuses; *client.Client satisfies it
+type StatusGetter interface {
+	GetDeliveryStatus(ctx context.Context, requestID string) (*models.DeliveryStatusResponse, error)
+}
+
+const defaultPollInterval = 10 * time.Second
+
+// PollDeliveryReports is the polling counterpart of Handler: it polls the
+// status of every request code at interval (10 seconds when not positive) and
+// dispatches a delivery report whenever an item's state changes. It returns
+// once every item of every request is terminal and every report was handled,
+// or with ctx's error. Lookup and handler failures are retried on the next poll.
+func PollDeliveryReports(ctx context.Context, getter StatusGetter, dispatcher *Dispatcher, requestCodes []string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	pending := make(map[string]bool, len(requestCodes))
+	for _, code := range requestCodes {
+		pending[code] = true
+	}
+
+	for {
+		for code := range pending {
+			resp, err := getter.GetDeliveryStatus(ctx, code)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				continue
+			}
+
+			done := len(resp.Data.SmsItems) > 0
+			for _, item := range resp.Data.SmsItems {
+				item := item
+				err := dispatcher.dispatchPolled(ctx, Event{Type: EventDeliveryReport, RequestCode: code, SmsItem: &item})
+				// Keep the request until its reports were handled, so a failed one is dispatched again
+				if err != nil || !item.Status.IsTerminal() {
+					done = false
+				}
+			}
+			if done {
+				delete(pending, code)
+			}
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// fakeStatusGetter returns the statuses of a request in turn, repeating the last one
+type fakeStatusGetter struct {
+	statuses []models.DeliveryState
+	calls    int
+}
+
+func (f *fakeStatusGetter) GetDeliveryStatus(ctx context.Context, requestID string) (*models.DeliveryStatusResponse, error) {
+	status := f.statuses[len(f.statuses)-1]
+	if f.calls < len(f.statuses) {
+		status = f.statuses[f.calls]
+	}
+	f.calls++
+
+	resp := &models.DeliveryStatusResponse{}
+	resp.Data.SmsItems = []models.SmsItemInfo{{SmsItemId: "1", Status: status}}
+	return resp, nil
+}
+
+func TestPollDeliveryReportsRedispatchesFailedReports(t *testing.T) {
+	getter := &fakeStatusGetter{statuses: []models.DeliveryState{models.StateDelivered}}
+	d := NewDispatcher()
+	var calls int
+	d.OnDeliveryReport(func(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+		calls++
+		if calls == 1 {
+			return errors.New("database down")
+		}
+		return nil
+	})
+
+	err := PollDeliveryReports(context.Background(), getter, d, []string{"rc1"}, time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollDeliveryReports() error = %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("handler calls = %d, want 2 (the failed report dispatched again)", calls)
+	}
+	if getter.calls != 2 {
+		t.Errorf("status lookups = %d, want 2", getter.calls)
+	}
+}
+
+func TestPollDeliveryReportsWaitsForTerminalStates(t *testing.T) {
+	getter := &fakeStatusGetter{statuses: []models.DeliveryState{models.StatePending, models.StateSent, models.StateDelivered}}
+	d := NewDispatcher()
+	var seen []models.DeliveryState
+	d.OnDeliveryReport(func(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+		seen = append(seen, item.Status)
+		return nil
+	})
+
+	if err := PollDeliveryReports(context.Background(), getter, d, []string{"rc1"}, time.Millisecond); err != nil {
+		t.Fatalf("PollDeliveryReports() error = %v", err)
+	}
+	if len(seen) != 3 || seen[2] != models.StateDelivered {
+		t.Errorf("dispatched states = %v, want Pending, Sent, Delivered", seen)
+	}
+}
+
+func TestPollDeliveryReportsDefaultsInterval(t *testing.T) {
+	getter := &fakeStatusGetter{statuses: []models.DeliveryState{models.StatePending}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := PollDeliveryReports(ctx, getter, NewDispatcher(), []string{"rc1"}, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("PollDeliveryReports() error = %v, want context.DeadlineExceeded", err)
+	}
+	if getter.calls != 1 {
+		t.Errorf("status lookups = %d, want 1 (a zero interval must not hot-loop)", getter.calls)
+	}
+}
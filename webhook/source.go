@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AryanHamedani/mediana-go-sdk/models"
+)
+
+// LocalSource pushes signed callbacks straight into an http.Handler, without
+// a network, so webhook flows can be exercised in tests
+type LocalSource struct {
+	handler http.Handler
+	secret  []byte
+}
+
+// NewLocalSource creates a source pushing to handler, signing with secret
+func NewLocalSource(handler http.Handler, secret []byte) *LocalSource {
+	return &LocalSource{handler: handler, secret: secret}
+}
+
+// Push signs and delivers the events as one callback and returns the handler's error, if any
+func (s *LocalSource) Push(ctx context.Context, events ...Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(s.secret, now, body))
+
+	recorder := &responseRecorder{header: make(http.Header)}
+	s.handler.ServeHTTP(recorder, req)
+	if recorder.code >= 300 {
+		return fmt.Errorf("callback rejected (%d): %s", recorder.code, bytes.TrimSpace(recorder.body.Bytes()))
+	}
+	return nil
+}
+
+// responseRecorder keeps the status and body the handler wrote
+type responseRecorder struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.code == 0 {
+		r.code = code
+	}
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(p)
+}
+
+// PushDeliveryReport pushes a delivery report for one SMS item
+func (s *LocalSource) PushDeliveryReport(ctx context.Context, requestCode string, item models.SmsItemInfo) error {
+	return s.Push(ctx, Event{Type: EventDeliveryReport, RequestCode: requestCode, SmsItem: &item})
+}
+
+// PushInboundMessage pushes an inbound message
+func (s *LocalSource) PushInboundMessage(ctx context.Context, msg models.InboxMessage) error {
+	return s.Push(ctx, Event{Type: EventInboundMessage, Message: &msg})
+}